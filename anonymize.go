@@ -0,0 +1,239 @@
+package goirsdk
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+	"gopkg.in/yaml.v3"
+)
+
+// AnonymizeOptions configures the anonymization of a telemetry file
+type AnonymizeOptions struct {
+	// Key seeds the pseudonyms. The same key gives a user the same pseudonym
+	// in every file, keep it secret or the pseudonyms of known user IDs can be
+	// recomputed. A random key is used when empty
+	Key []byte
+	// DropGPS removes the Lat and Lon variables
+	DropGPS bool
+}
+
+// gpsVars are the variables holding the position of the car on the globe
+var gpsVars = map[string]bool{"Lat": true, "Lon": true}
+
+// genericFrequencies are the radio frequencies every session has, their names
+// identify no one
+var genericFrequencies = map[string]bool{
+	"@ALLTEAMS":    true,
+	"@DRIVERS":     true,
+	"@RACECONTROL": true,
+	"@ADMIN":       true,
+	"@PRIVATE":     true,
+	"@TEAM":        true,
+	"@CLUB":        true,
+}
+
+// Anonymize writes a copy of the telemetry file into dst with the identifying
+// data stripped from the session info: user names, IDs, teams and clubs of
+// the drivers, the league, the radio frequency names and the setup name.
+// Users get a pseudonym derived from their ID, so every mention of them stays
+// consistent
+func (i *IBT) Anonymize(dst io.WriterAt, opts AnonymizeOptions) error {
+	raw, err := i.rawSessionInfo()
+	if err != nil {
+		return err
+	}
+
+	key := opts.Key
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		_, err = rand.Read(key)
+		if err != nil {
+			return fmt.Errorf("failed to generate pseudonym key: %v", err)
+		}
+	}
+
+	sessionInfo, err := AnonymizeSessionInfo(raw, key)
+	if err != nil {
+		return err
+	}
+
+	vars := i.SortedVars()
+	bufLen := i.Headers.BufLen
+	if opts.DropGPS {
+		var names []string
+		for _, v := range vars {
+			if !gpsVars[v.Name] {
+				names = append(names, v.Name)
+			}
+		}
+		vars, bufLen, err = i.SubsetVars(names)
+		if err != nil {
+			return err
+		}
+	}
+
+	return i.rewrite(dst, sessionInfo, vars, bufLen, nil)
+}
+
+// AnonymizeSessionInfo strips the identifying data out of a raw session info
+// string, as stored in the telemetry data. The result is Windows-1252 encoded
+// like the input
+func AnonymizeSessionInfo(raw []byte, key []byte) ([]byte, error) {
+	decoded, err := charmap.Windows1252.NewDecoder().Bytes(bytes.TrimRight(raw, "\x00"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode session info: %v", err)
+	}
+
+	var doc yaml.Node
+	err = yaml.Unmarshal(decoded, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse session info: %v", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("the session info is empty")
+	}
+
+	p := pseudonymizer{key: key}
+	root := doc.Content[0]
+	if weekend := mappingValue(root, "WeekendInfo"); weekend != nil {
+		setScalar(weekend, "LeagueID", "0")
+	}
+	if radio := mappingValue(root, "RadioInfo"); radio != nil {
+		for _, r := range sequenceItems(mappingValue(radio, "Radios")) {
+			for _, f := range sequenceItems(mappingValue(r, "Frequencies")) {
+				p.frequency(f)
+			}
+		}
+	}
+	if drivers := mappingValue(root, "DriverInfo"); drivers != nil {
+		if id := mappingValue(drivers, "DriverUserID"); id != nil {
+			id.Value = p.userID(id.Value)
+		}
+		if setup := mappingValue(drivers, "DriverSetupName"); setup != nil && setup.Value != "" {
+			setup.Value = "anonymous.sto"
+		}
+		for _, d := range sequenceItems(mappingValue(drivers, "Drivers")) {
+			p.driver(d)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	err = enc.Encode(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode session info: %v", err)
+	}
+	enc.Close()
+	buf.WriteString("...\n")
+
+	encoded, err := charmap.Windows1252.NewEncoder().Bytes(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode session info: %v", err)
+	}
+	return encoded, nil
+}
+
+// pseudonymizer derives stable pseudonyms out of identifiers
+type pseudonymizer struct {
+	key []byte
+}
+
+// hash returns a positive number derived from an identifier
+func (p pseudonymizer) hash(kind string, id string) uint32 {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(kind + ":" + id))
+	return binary.LittleEndian.Uint32(mac.Sum(nil)) & 0x7FFFFFFF
+}
+
+// userID maps a user ID to its pseudonym. Non positive IDs, like the pace
+// car's, are kept as they are
+func (p pseudonymizer) userID(id string) string {
+	if n, err := strconv.Atoi(id); err != nil || n <= 0 {
+		return id
+	}
+	return strconv.FormatUint(uint64(p.hash("user", id)), 10)
+}
+
+// driver anonymizes an entry of DriverInfo.Drivers
+func (p pseudonymizer) driver(d *yaml.Node) {
+	if paceCar := mappingValue(d, "CarIsPaceCar"); paceCar != nil && paceCar.Value == "1" {
+		return
+	}
+
+	id := mappingValue(d, "UserID")
+	if id == nil {
+		return
+	}
+	pseudoID := p.userID(id.Value)
+	id.Value = pseudoID
+
+	name := "Driver " + pseudoID
+	setScalar(d, "UserName", name)
+	setScalar(d, "AbbrevName", name)
+	setScalar(d, "Initials", "")
+	setScalar(d, "ClubID", "0")
+	setScalar(d, "ClubName", "")
+
+	team := mappingValue(d, "TeamID")
+	if team != nil && team.Value != "0" {
+		team.Value = strconv.FormatUint(uint64(p.hash("team", team.Value)), 10)
+		setScalar(d, "TeamName", "Team "+team.Value)
+		return
+	}
+	// Solo entries use the driver's name as the team name
+	setScalar(d, "TeamName", name)
+}
+
+// frequency anonymizes a radio frequency, the private ones are named after
+// drivers, teams or leagues
+func (p pseudonymizer) frequency(f *yaml.Node) {
+	setScalar(f, "ClubID", "0")
+
+	name := mappingValue(f, "FrequencyName")
+	if name == nil || genericFrequencies[strings.ToUpper(name.Value)] {
+		return
+	}
+	num := "0"
+	if n := mappingValue(f, "FrequencyNum"); n != nil {
+		num = n.Value
+	}
+	name.Value = "@FREQUENCY" + num
+}
+
+// mappingValue returns the value of key in a YAML mapping, nil when missing
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for k := 0; k+1 < len(node.Content); k += 2 {
+		if node.Content[k].Value == key {
+			return node.Content[k+1]
+		}
+	}
+	return nil
+}
+
+// sequenceItems returns the items of a YAML sequence, nil for anything else
+func sequenceItems(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
+
+// setScalar replaces the value of key in a YAML mapping when it is present
+func setScalar(node *yaml.Node, key string, value string) {
+	if v := mappingValue(node, key); v != nil && v.Kind == yaml.ScalarNode {
+		v.Value = value
+	}
+}
@@ -0,0 +1,113 @@
+package goirsdk
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestAnonymize_SessionInfo
+// Identifying data is replaced and users keep the same pseudonym everywhere
+func TestAnonymize_SessionInfo(t *testing.T) {
+	// Arrange
+	source := openTestIBT(t, writeTestIBT(t, 30))
+	opts := AnonymizeOptions{Key: []byte("secret")}
+
+	// Act
+	path := rewriteTestIBT(t, func(dst *os.File) error {
+		return source.Anonymize(dst, opts)
+	})
+	again := rewriteTestIBT(t, func(dst *os.File) error {
+		return source.Anonymize(dst, opts)
+	})
+
+	// Assert
+	ibt := openTestIBT(t, path)
+	info := ibt.SessionInfo
+	if info.WeekendInfo.LeagueID != 0 {
+		t.Fatalf("Expected LeagueID 0, got %d", info.WeekendInfo.LeagueID)
+	}
+	if info.WeekendInfo.TrackID != 166 || info.WeekendInfo.SubSessionID != 12345 {
+		t.Fatalf("Expected the session identifiers to be kept")
+	}
+	if info.DriverInfo.DriverSetupName != "anonymous.sto" {
+		t.Fatalf("Expected the setup name to be replaced, got %q", info.DriverInfo.DriverSetupName)
+	}
+
+	me := info.DriverInfo.Drivers[0]
+	if me.UserID == 4242 || info.DriverInfo.DriverUserID != me.UserID {
+		t.Fatalf("Expected DriverUserID and UserID to share a pseudonym, got %d and %d",
+			info.DriverInfo.DriverUserID, me.UserID)
+	}
+	for _, d := range info.DriverInfo.Drivers {
+		expected := fmt.Sprintf("Driver %d", d.UserID)
+		if d.UserName != expected || d.TeamName != expected {
+			t.Fatalf("Driver %d was not anonymized: %q, %q", d.CarIdx, d.UserName, d.TeamName)
+		}
+	}
+
+	frequencies := info.RadioInfo.Radios[0].Frequencies
+	if frequencies[0].FrequencyName != "@ALLTEAMS" || frequencies[1].FrequencyName != "@FREQUENCY1" {
+		t.Fatalf("Unexpected frequency names %q and %q",
+			frequencies[0].FrequencyName, frequencies[1].FrequencyName)
+	}
+	if frequencies[1].ClubID != 0 {
+		t.Fatalf("Expected ClubID 0, got %d", frequencies[1].ClubID)
+	}
+
+	other := openTestIBT(t, again).SessionInfo
+	if !cmp.Equal(info.DriverInfo.Drivers, other.DriverInfo.Drivers) {
+		t.Fatalf("Pseudonyms differ between runs with the same key:\n%s",
+			cmp.Diff(info.DriverInfo.Drivers, other.DriverInfo.Drivers))
+	}
+}
+
+// TestAnonymize_DropGPS
+// The Lat and Lon variables are removed and the rest of the data is kept
+func TestAnonymize_DropGPS(t *testing.T) {
+	// Arrange
+	vars := append(append([]Var{}, testVars...),
+		Var{Type: IRSDK_double, Offset: 48, Count: 1, Name: "Lat", Unit: "rad"},
+		Var{Type: IRSDK_double, Offset: 56, Count: 1, Name: "Lon", Unit: "rad"},
+	)
+	source := openTestIBT(t, rewriteTestIBT(t, func(dst *os.File) error {
+		headers := TelemetryHeaders{Version: 2, Status: 1, TickRate: 60, BufLen: 64}
+		w, err := NewIBTWriter(dst, headers, DiskSubHeader{}, vars, []byte(testSessionInfo))
+		if err != nil {
+			return err
+		}
+		for tick := 0; tick < 10; tick++ {
+			frame := append(testFrame(tick), make([]byte, 16)...)
+			err = w.WriteFrame(frame)
+			if err != nil {
+				return err
+			}
+		}
+		return w.Close()
+	}))
+
+	// Act
+	path := rewriteTestIBT(t, func(dst *os.File) error {
+		return source.Anonymize(dst, AnonymizeOptions{DropGPS: true})
+	})
+
+	// Assert
+	ibt := openTestIBT(t, path)
+	for _, name := range []string{"Lat", "Lon"} {
+		if _, ok := ibt.Vars.Vars[name]; ok {
+			t.Fatalf("Expected %s to be dropped", name)
+		}
+	}
+	if ibt.SubHeaders.RecordCount != 10 {
+		t.Fatalf("Expected 10 frames, got %d", ibt.SubHeaders.RecordCount)
+	}
+	_, err := ibt.Update(0)
+	if err != nil {
+		t.Fatalf("Failed to read anonymized file: %v", err)
+	}
+	if got := ibt.Vars.Vars["Speed"].Value.(float32); got != 0 {
+		t.Fatalf("Expected Speed 0 on the first frame, got %v", got)
+	}
+}
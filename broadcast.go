@@ -0,0 +1,195 @@
+package goirsdk
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoBroadcast means there's no way to send broadcast messages, the data
+// doesn't come from a live session and no transport was set
+var ErrNoBroadcast = errors.New("no broadcast transport for this source")
+
+// BroadcastTransport delivers broadcast messages to the simulator. The live
+// source sends them with the Windows broadcast channel, SetBroadcastTransport
+// replaces it, with a RecordingTransport in tests for example
+type BroadcastTransport interface {
+	Broadcast(m Msg) error
+}
+
+// Params packs a message into the two parameters of the Windows message:
+// the command and P1 in the low and high words of the first, P2 and P3 in
+// the low and high words of the second
+func (m Msg) Params() (uintptr, uintptr) {
+	wParam := uint32(uint16(m.Cmd)) | uint32(uint16(m.P1))<<16
+	lParam := uint32(uint16(m.P2)) | uint32(uint16(m.P3))<<16
+	return uintptr(wParam), uintptr(lParam)
+}
+
+// RecordingTransport keeps the messages instead of sending them
+type RecordingTransport struct {
+	mu   sync.Mutex
+	msgs []Msg
+}
+
+// Broadcast records m
+func (t *RecordingTransport) Broadcast(m Msg) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.msgs = append(t.msgs, m)
+	return nil
+}
+
+// Messages returns the recorded messages, oldest first
+func (t *RecordingTransport) Messages() []Msg {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Msg(nil), t.msgs...)
+}
+
+// Reset forgets the recorded messages
+func (t *RecordingTransport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.msgs = nil
+}
+
+// SetBroadcastTransport sets where the broadcast messages go, nil goes back to
+// the transport of the source
+func (i *IBT) SetBroadcastTransport(t BroadcastTransport) {
+	i.broadcaster = t
+}
+
+// broadcast sends a message with the transport in use
+func (i *IBT) broadcast(cmd int, p1 int, p2 int, p3 int) error {
+	for _, p := range []int{p1, p2, p3} {
+		if p < math.MinInt16 || p > math.MaxUint16 {
+			return fmt.Errorf("parameter %d of broadcast %d doesn't fit 16 bits", p, cmd)
+		}
+	}
+
+	t := i.broadcaster
+	if t == nil {
+		var ok bool
+		t, ok = i.source.(BroadcastTransport)
+		if !ok {
+			return ErrNoBroadcast
+		}
+	}
+
+	return t.Broadcast(Msg{Cmd: cmd, P1: int32(p1), P2: int32(p2), P3: int32(p3)})
+}
+
+// broadcastInt sends a message whose last parameter is a 32 bits value split
+// in its low and high words
+func (i *IBT) broadcastInt(cmd int, p1 int, value int32) error {
+	return i.broadcast(cmd, p1, int(uint32(value)&0xFFFF), int(uint32(value)>>16))
+}
+
+// CamSwitchPos focuses the camera on the car at a race position, or on one of
+// the FocusAt targets, with a camera group and camera. 0 keeps the current
+// group or camera
+func (i *IBT) CamSwitchPos(position int, group int, camera int) error {
+	return i.broadcast(BroadcastCamSwitchPos, position, group, camera)
+}
+
+// CamSwitchNum focuses the camera on the car with a car number, as shown on
+// the car ("7", "007"...), with a camera group and camera
+func (i *IBT) CamSwitchNum(carNumber string, group int, camera int) error {
+	num, err := padCarNumber(carNumber)
+	if err != nil {
+		return err
+	}
+	return i.broadcast(BroadcastCamSwitchNum, num, group, camera)
+}
+
+// padCarNumber encodes the leading zeros of a car number the way the
+// simulator expects them: "007" is 7 plus 1000 times the number of digits
+// including the zeros
+func padCarNumber(carNumber string) (int, error) {
+	num, err := strconv.Atoi(carNumber)
+	if err != nil || num < 0 {
+		return 0, fmt.Errorf("invalid car number %q", carNumber)
+	}
+
+	zeros := len(carNumber) - len(strings.TrimLeft(carNumber, "0"))
+	if zeros == len(carNumber) {
+		// "0" and "00" keep a digit for the number itself
+		zeros--
+	}
+	if zeros == 0 {
+		return num, nil
+	}
+	return num + 1000*(len(strconv.Itoa(num))+zeros), nil
+}
+
+// CamSetState sets the camera tool state, a combination of the
+// irsdk_CameraState flags
+func (i *IBT) CamSetState(state int) error {
+	return i.broadcast(BroadcastCamSetState, state, 0, 0)
+}
+
+// ReplaySetPlaySpeed sets the replay speed, negative to rewind. With
+// slowMotion the speed is the divider of the normal speed
+func (i *IBT) ReplaySetPlaySpeed(speed int, slowMotion bool) error {
+	slow := 0
+	if slowMotion {
+		slow = 1
+	}
+	return i.broadcast(BroadcastReplaySetPlaySpeed, speed, slow, 0)
+}
+
+// ReplaySetPlayPosition moves the replay to a frame, counted from the RpyPos
+// mode: the beginning, the current frame or the end
+func (i *IBT) ReplaySetPlayPosition(mode int, frame int32) error {
+	return i.broadcastInt(BroadcastReplaySetPlayPosition, mode, frame)
+}
+
+// ReplaySearch moves the replay with a RpySrch mode: to the start, the next
+// lap, the previous incident...
+func (i *IBT) ReplaySearch(mode int) error {
+	return i.broadcast(BroadcastReplaySearch, mode, 0, 0)
+}
+
+// ReplaySetState changes the replay tape with a RpyState mode
+func (i *IBT) ReplaySetState(mode int) error {
+	return i.broadcast(BroadcastReplaySetState, mode, 0, 0)
+}
+
+// ReloadTextures reloads the textures of every car, or of carIdx with
+// ReloadTexturesCarIdx
+func (i *IBT) ReloadTextures(mode int, carIdx int) error {
+	return i.broadcast(BroadcastReloadTextures, mode, carIdx, 0)
+}
+
+// ChatCommand drives the chat window with a ChatCommand mode, macro is the
+// chat macro number (1-15) for ChatCommandMacro
+func (i *IBT) ChatCommand(mode int, macro int) error {
+	return i.broadcast(BroadcastChatComand, mode, macro, 0)
+}
+
+// PitCommand sends a PitCommand mode with its parameter: liters of fuel, tire
+// pressure in KPa or 0 to keep the current value
+func (i *IBT) PitCommand(mode int, parameter int32) error {
+	return i.broadcastInt(BroadcastPitCommand, mode, parameter)
+}
+
+// TelemCommand starts, stops or restarts the telemetry recording
+func (i *IBT) TelemCommand(mode int) error {
+	return i.broadcast(BroadcastTelemCommand, mode, 0, 0)
+}
+
+// FFBCommand sends a FFBCommand mode with its value, in Nm for
+// FFBCommandMaxForce. The value is sent in 16.16 fixed point
+func (i *IBT) FFBCommand(mode int, value float32) error {
+	return i.broadcastInt(BroadcastFFBCommand, mode, int32(value*65536))
+}
+
+// ReplaySearchSessionTime moves the replay to a session time of a session
+func (i *IBT) ReplaySearchSessionTime(sessionNum int, sessionTime time.Duration) error {
+	return i.broadcastInt(BroadcastReplaySearchSessionTime, sessionNum, int32(sessionTime.Milliseconds()))
+}
@@ -0,0 +1,88 @@
+package goirsdk
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestMsg_Params
+// The parameters are packed in the low and high words of the Windows message
+func TestMsg_Params(t *testing.T) {
+	// Arrange
+	m := Msg{Cmd: BroadcastCamSwitchPos, P1: 3, P2: 0x1234, P3: -1}
+
+	// Act
+	wParam, lParam := m.Params()
+
+	// Assert
+	if wParam != 0x00030000 {
+		t.Errorf("Expected wParam 0x00030000, got %#x", wParam)
+	}
+	if lParam != 0xFFFF1234 {
+		t.Errorf("Expected lParam 0xFFFF1234, got %#x", lParam)
+	}
+}
+
+// TestBroadcast_Messages
+// The typed methods send the messages of the SDK through the transport
+func TestBroadcast_Messages(t *testing.T) {
+	// Arrange
+	ibt := openTestIBT(t, writeTestIBT(t, 1))
+	rec := &RecordingTransport{}
+	ibt.SetBroadcastTransport(rec)
+
+	// Act
+	calls := []error{
+		ibt.CamSwitchNum("007", 2, 0),
+		ibt.ReplaySetPlaySpeed(4, true),
+		ibt.ReplaySetPlayPosition(RpyPosBegin, 0x12345),
+		ibt.PitCommand(PitCommandFuel, 20),
+		ibt.FFBCommand(FFBCommandMaxForce, 1.5),
+		ibt.ReplaySearchSessionTime(2, 90*time.Second),
+	}
+
+	// Assert
+	for k, err := range calls {
+		if err != nil {
+			t.Fatalf("Call %d failed: %v", k, err)
+		}
+	}
+	expected := []Msg{
+		{Cmd: BroadcastCamSwitchNum, P1: 3007, P2: 2, P3: 0},
+		{Cmd: BroadcastReplaySetPlaySpeed, P1: 4, P2: 1, P3: 0},
+		{Cmd: BroadcastReplaySetPlayPosition, P1: int32(RpyPosBegin), P2: 0x2345, P3: 0x1},
+		{Cmd: BroadcastPitCommand, P1: int32(PitCommandFuel), P2: 20, P3: 0},
+		{Cmd: BroadcastFFBCommand, P1: int32(FFBCommandMaxForce), P2: 0x8000, P3: 0x1},
+		{Cmd: BroadcastReplaySearchSessionTime, P1: 2, P2: 90000 & 0xFFFF, P3: 90000 >> 16},
+	}
+	if diff := cmp.Diff(expected, rec.Messages()); diff != "" {
+		t.Errorf("Messages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestBroadcast_Errors
+// Files have no broadcast channel and parameters must fit 16 bits
+func TestBroadcast_Errors(t *testing.T) {
+	// Arrange
+	ibt := openTestIBT(t, writeTestIBT(t, 1))
+
+	// Act
+	errNoTransport := ibt.ReplaySearch(RpySrchNextLap)
+	ibt.SetBroadcastTransport(&RecordingTransport{})
+	errRange := ibt.CamSwitchPos(70000, 0, 0)
+	errNumber := ibt.CamSwitchNum("12a", 0, 0)
+
+	// Assert
+	if !errors.Is(errNoTransport, ErrNoBroadcast) {
+		t.Errorf("Expected ErrNoBroadcast, got %v", errNoTransport)
+	}
+	if errRange == nil {
+		t.Errorf("Expected an error for a parameter over 16 bits")
+	}
+	if errNumber == nil {
+		t.Errorf("Expected an error for an invalid car number")
+	}
+}
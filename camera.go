@@ -0,0 +1,180 @@
+package goirsdk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// cameraStateMutable are the CamCameraState flags a broadcast message can
+// change
+const cameraStateMutable = CameraCamToolActive | CameraUIHidden | CameraUseAutoShotSelection |
+	CameraUseTemporaryEdits | CameraUseKeyAcceleration | CameraUseKey10xAcceleration |
+	CameraUseMouseAimMode
+
+// Camera is a camera of a camera group
+type Camera struct {
+	Num  int
+	Name string
+}
+
+// CameraGroup is a camera group of the CameraInfo of the session info
+type CameraGroup struct {
+	Num      int
+	Name     string
+	IsScenic bool // Scenic groups don't follow a car
+	Cameras  []Camera
+}
+
+// Camera finds a camera of the group by name, ignoring case
+func (g CameraGroup) Camera(name string) (Camera, bool) {
+	for _, c := range g.Cameras {
+		if strings.EqualFold(c.Name, name) {
+			return c, true
+		}
+	}
+	return Camera{}, false
+}
+
+// CameraCatalog lists the camera groups of a session
+type CameraCatalog struct {
+	Groups []CameraGroup
+}
+
+// Group finds a camera group by name, ignoring case
+func (c CameraCatalog) Group(name string) (CameraGroup, bool) {
+	for _, g := range c.Groups {
+		if strings.EqualFold(g.Name, name) {
+			return g, true
+		}
+	}
+	return CameraGroup{}, false
+}
+
+// Cameras returns the camera groups of the session info
+func (i *IBT) Cameras() CameraCatalog {
+	var catalog CameraCatalog
+	if i.SessionInfo == nil {
+		return catalog
+	}
+
+	for _, g := range i.SessionInfo.CameraInfo.Groups {
+		group := CameraGroup{Num: g.GroupNum, Name: g.GroupName, IsScenic: g.IsScenic}
+		for _, c := range g.Cameras {
+			group.Cameras = append(group.Cameras, Camera{Num: c.CameraNum, Name: c.CameraName})
+		}
+		catalog.Groups = append(catalog.Groups, group)
+	}
+	return catalog
+}
+
+// CameraDirector picks what the camera of the simulator shows by car number,
+// position and camera group name instead of raw broadcast parameters
+type CameraDirector struct {
+	ibt *IBT
+}
+
+// NewCameraDirector creates a director sending its commands through ibt
+func NewCameraDirector(ibt *IBT) *CameraDirector {
+	return &CameraDirector{ibt: ibt}
+}
+
+// ShowCar focuses on the car with a car number, "12" or "#12", with a camera
+// group. An empty group keeps the current one
+func (d *CameraDirector) ShowCar(carNumber string, group string) error {
+	carNumber = strings.TrimPrefix(carNumber, "#")
+	if d.ibt.SessionInfo != nil {
+		found := false
+		for _, driver := range d.ibt.SessionInfo.DriverInfo.Drivers {
+			if driver.CarNumber == carNumber {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("there's no car #%s in the session", carNumber)
+		}
+	}
+
+	groupNum, err := d.groupNum(group)
+	if err != nil {
+		return err
+	}
+	return d.ibt.CamSwitchNum(carNumber, groupNum, 0)
+}
+
+// ShowPosition focuses on the car at a race position, starting at 1
+func (d *CameraDirector) ShowPosition(position int, group string) error {
+	if position < 1 {
+		return fmt.Errorf("invalid race position %d", position)
+	}
+	return d.focus(position, group)
+}
+
+// ShowLeader focuses on the leader of the race
+func (d *CameraDirector) ShowLeader(group string) error {
+	return d.focus(FocusAtLeader, group)
+}
+
+// ShowIncident focuses on the latest incident
+func (d *CameraDirector) ShowIncident(group string) error {
+	return d.focus(FocusAtIncident, group)
+}
+
+// ShowExiting focuses on the car exiting the pits
+func (d *CameraDirector) ShowExiting(group string) error {
+	return d.focus(FocusAtExiting, group)
+}
+
+// focus switches to a position or FocusAt target with a named group
+func (d *CameraDirector) focus(target int, group string) error {
+	groupNum, err := d.groupNum(group)
+	if err != nil {
+		return err
+	}
+	return d.ibt.CamSwitchPos(target, groupNum, 0)
+}
+
+// groupNum finds the number of a camera group, 0 for the current group
+func (d *CameraDirector) groupNum(name string) (int, error) {
+	if name == "" {
+		return 0, nil
+	}
+	g, ok := d.ibt.Cameras().Group(name)
+	if !ok {
+		return 0, fmt.Errorf("there's no camera group %q in the session", name)
+	}
+	return g.Num, nil
+}
+
+// State returns the CamCameraState of the last frame read by Update
+func (d *CameraDirector) State() (int, bool) {
+	v, ok := d.ibt.Vars.Vars["CamCameraState"]
+	if !ok || d.ibt.frame == nil {
+		return 0, false
+	}
+	return int(entryNumber(v, d.ibt.frame, 0)), true
+}
+
+// SetState replaces the camera state flags that can be changed, the Camera*
+// constants after CameraIsScenicActive
+func (d *CameraDirector) SetState(flags int) error {
+	return d.ibt.CamSetState(flags & cameraStateMutable)
+}
+
+// EnableState sets flags on top of the current camera state
+func (d *CameraDirector) EnableState(flags int) error {
+	state, ok := d.State()
+	if !ok {
+		return fmt.Errorf("the camera state isn't known before the first Update")
+	}
+	return d.SetState(state | flags)
+}
+
+// DisableState clears flags from the current camera state
+func (d *CameraDirector) DisableState(flags int) error {
+	state, ok := d.State()
+	if !ok {
+		return fmt.Errorf("the camera state isn't known before the first Update")
+	}
+	return d.SetState(state &^ flags)
+}
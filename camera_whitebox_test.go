@@ -0,0 +1,75 @@
+package goirsdk
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestCameras_Catalog
+// The camera groups of the session info are found by name
+func TestCameras_Catalog(t *testing.T) {
+	// Arrange
+	ibt := openTestIBT(t, writeTestIBT(t, 1))
+
+	// Act
+	catalog := ibt.Cameras()
+	group, ok := catalog.Group("tv1")
+
+	// Assert
+	if len(catalog.Groups) != 3 {
+		t.Fatalf("Expected 3 camera groups, got %d", len(catalog.Groups))
+	}
+	if !ok {
+		t.Fatalf("Expected to find the TV1 group")
+	}
+	camera, ok := group.Camera("CamTV1_01")
+	if !ok || camera.Num != 2 || group.Num != 10 {
+		t.Errorf("Expected group 10 camera 2, got group %d camera %v", group.Num, camera)
+	}
+	if blimp, _ := catalog.Group("Blimp"); !blimp.IsScenic {
+		t.Errorf("Expected the Blimp group to be scenic")
+	}
+}
+
+// TestCameraDirector_Focus
+// The director translates car numbers, targets and group names into the
+// camera broadcast messages
+func TestCameraDirector_Focus(t *testing.T) {
+	// Arrange
+	ibt := openTestIBT(t, writeTestIBT(t, 1))
+	rec := &RecordingTransport{}
+	ibt.SetBroadcastTransport(rec)
+	director := NewCameraDirector(ibt)
+
+	// Act
+	calls := []error{
+		director.ShowCar("#12", "TV1"),
+		director.ShowLeader("Blimp"),
+		director.ShowIncident(""),
+		director.ShowPosition(3, "Nose"),
+		director.SetState(CameraIsSessionScreen | CameraUIHidden),
+	}
+	errCar := director.ShowCar("99", "TV1")
+	errGroup := director.ShowLeader("Helicopter")
+
+	// Assert
+	for k, err := range calls {
+		if err != nil {
+			t.Fatalf("Call %d failed: %v", k, err)
+		}
+	}
+	if errCar == nil || errGroup == nil {
+		t.Errorf("Expected errors for an unknown car and group, got %v and %v", errCar, errGroup)
+	}
+	expected := []Msg{
+		{Cmd: BroadcastCamSwitchNum, P1: 12, P2: 10},
+		{Cmd: BroadcastCamSwitchPos, P1: int32(FocusAtLeader), P2: 21},
+		{Cmd: BroadcastCamSwitchPos, P1: int32(FocusAtIncident)},
+		{Cmd: BroadcastCamSwitchPos, P1: 3, P2: 1},
+		{Cmd: BroadcastCamSetState, P1: int32(CameraUIHidden)},
+	}
+	if diff := cmp.Diff(expected, rec.Messages()); diff != "" {
+		t.Errorf("Messages mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ESilva15/goirsdk"
+)
+
+func runAnonymize(args []string) error {
+	fs := flag.NewFlagSet("anonymize", flag.ExitOnError)
+	in := fs.String("in", "", "source .ibt file")
+	out := fs.String("out", "", "destination .ibt file")
+	keyFile := fs.String("key-file", "", "file with the secret key for stable pseudonyms, random by default")
+	dropGPS := fs.Bool("drop-gps", false, "remove the Lat and Lon variables")
+	fs.Parse(args)
+
+	if *in == "" || *out == "" {
+		return fmt.Errorf("both -in and -out are required")
+	}
+
+	opts := goirsdk.AnonymizeOptions{DropGPS: *dropGPS}
+	if *keyFile != "" {
+		key, err := os.ReadFile(*keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to read key file: %v", err)
+		}
+		opts.Key = []byte(strings.TrimSpace(string(key)))
+		if len(opts.Key) == 0 {
+			return fmt.Errorf("the key file is empty")
+		}
+	}
+
+	ibt, file, err := openIBT(*in)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	defer ibt.Close()
+
+	dst, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer dst.Close()
+
+	return ibt.Anonymize(dst, opts)
+}
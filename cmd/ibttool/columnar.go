@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/ESilva15/goirsdk"
+	"github.com/ESilva15/goirsdk/columnar"
+)
+
+func runParquet(args []string) error {
+	return runColumnar("parquet", args, columnar.WriteParquet)
+}
+
+func runArrow(args []string) error {
+	return runColumnar("arrow", args, columnar.WriteArrow)
+}
+
+func runColumnar(name string, args []string,
+	write func(io.Writer, *goirsdk.IBT, columnar.Options) error) error {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	in := fs.String("in", "", "source .ibt file")
+	out := fs.String("out", "", "destination file")
+	vars := fs.String("vars", "", "comma separated variables to export, all by default")
+	varsFile := fs.String("vars-file", "", "file with one variable to export per line")
+	batch := fs.Int("batch", 0, "frames per record batch / row group")
+	fs.Parse(args)
+
+	if *in == "" || *out == "" {
+		return fmt.Errorf("both -in and -out are required")
+	}
+
+	names, err := parseNames(*vars, *varsFile)
+	if err != nil {
+		return err
+	}
+
+	ibt, file, err := openIBT(*in)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	defer ibt.Close()
+
+	dst, closeDst, err := createOutput(*out)
+	if err != nil {
+		return err
+	}
+	defer closeDst()
+
+	w := bufio.NewWriter(dst)
+	err = write(w, ibt, columnar.Options{Vars: names, BatchSize: *batch})
+	if err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
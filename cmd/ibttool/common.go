@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ESilva15/goirsdk"
+)
+
+// openIBT opens a telemetry file, compressed or not, and hands it to the SDK.
+// The returned file is owned by the caller
+func openIBT(path string) (*goirsdk.IBT, *os.File, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open IBT file: %v", err)
+	}
+
+	var src goirsdk.Reader = file
+	if goirsdk.IsCompressed(file) {
+		src, err = openCompressed(file)
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+	}
+
+	ibt, err := goirsdk.Init(src, "", "")
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to read IBT file: %v", err)
+	}
+
+	return ibt, file, nil
+}
+
+// openCompressed wraps a compressed telemetry file
+func openCompressed(file *os.File) (*goirsdk.CompressedReader, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat IBT file: %v", err)
+	}
+
+	r, err := goirsdk.NewCompressedReader(file, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compressed IBT file: %v", err)
+	}
+	return r, nil
+}
+
+// splitRange splits a "from:to" string
+func splitRange(s string) (string, string, error) {
+	from, to, ok := strings.Cut(s, ":")
+	if !ok {
+		return "", "", fmt.Errorf("range %q is not in the from:to form", s)
+	}
+	return from, to, nil
+}
+
+// parseInts parses a comma separated list of integers
+func parseInts(s string) ([]int, error) {
+	var values []int
+	for _, field := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q: %v", field, err)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ESilva15/goirsdk"
+)
+
+func runCompress(args []string) error {
+	fs := flag.NewFlagSet("compress", flag.ExitOnError)
+	in := fs.String("in", "", "source .ibt file")
+	out := fs.String("out", "", "destination file")
+	chunk := fs.Int("chunk", goirsdk.DefaultCompressedSize, "uncompressed size of each chunk in bytes")
+	level := fs.Int("level", gzip.DefaultCompression, "gzip compression level, 1 to 9")
+	decompress := fs.Bool("d", false, "decompress a compressed .ibt back into a plain one")
+	fs.Parse(args)
+
+	if *in == "" || *out == "" {
+		return fmt.Errorf("both -in and -out are required")
+	}
+
+	src, err := os.Open(*in)
+	if err != nil {
+		return fmt.Errorf("failed to open source file: %v", err)
+	}
+	defer src.Close()
+
+	dst, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer dst.Close()
+
+	if *decompress {
+		r, err := openCompressed(src)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(dst, r)
+		return err
+	}
+
+	w := bufio.NewWriter(dst)
+	cw, err := goirsdk.NewCompressedWriterLevel(w, *chunk, *level)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(cw, src)
+	if err != nil {
+		return fmt.Errorf("failed to compress: %v", err)
+	}
+	err = cw.Close()
+	if err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
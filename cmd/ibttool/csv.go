@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ESilva15/goirsdk"
+)
+
+func runCSV(args []string) error {
+	fs := flag.NewFlagSet("csv", flag.ExitOnError)
+	in := fs.String("in", "", "source .ibt file")
+	out := fs.String("out", "-", "destination .csv file, - for stdout")
+	vars := fs.String("vars", "", "comma separated variables to export, all by default")
+	varsFile := fs.String("vars-file", "", "file with one variable to export per line")
+	rate := fs.Int("rate", 0, "output rate in Hz, a divisor of the tick rate, every frame by default")
+	types := fs.Bool("types", false, "add a row with the irsdk type of every column")
+	fs.Parse(args)
+
+	if *in == "" {
+		return fmt.Errorf("-in is required")
+	}
+
+	names, err := parseNames(*vars, *varsFile)
+	if err != nil {
+		return err
+	}
+
+	ibt, file, err := openIBT(*in)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	defer ibt.Close()
+
+	dst, closeDst, err := createOutput(*out)
+	if err != nil {
+		return err
+	}
+	defer closeDst()
+
+	w := bufio.NewWriter(dst)
+	err = ibt.ExportCSV(w, goirsdk.CSVOptions{Vars: names, Rate: *rate, TypesRow: *types})
+	if err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+// createOutput opens the output file, - stands for stdout
+func createOutput(path string) (io.Writer, func(), error) {
+	if path == "-" {
+		return os.Stdout, func() {}, nil
+	}
+
+	dst, err := os.Create(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create output file: %v", err)
+	}
+
+	return dst, func() { dst.Close() }, nil
+}
@@ -0,0 +1,84 @@
+//go:build linux && cgo
+// +build linux,cgo
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+
+	"github.com/ESilva15/goirsdk"
+)
+
+// The emulator needs POSIX shared memory, it is only available on Linux
+func init() {
+	commands = append(commands, command{"emulate", "publish an .ibt as a live session in shared memory", runEmulate})
+}
+
+func runEmulate(args []string) error {
+	fs := flag.NewFlagSet("emulate", flag.ExitOnError)
+	in := fs.String("in", "", "source .ibt file")
+	speed := fs.Float64("speed", 1, "playback speed, 0 publishes as fast as possible")
+	loop := fs.Bool("loop", false, "restart from the first frame after the last one")
+	simStatus := fs.Bool("sim-status", false, "answer the sim status requests like iRacing does")
+	fs.Parse(args)
+
+	if *in == "" {
+		return fmt.Errorf("-in is required")
+	}
+
+	ibt, file, err := openIBT(*in)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	defer ibt.Close()
+
+	emu, err := goirsdk.NewEmulator(ibt, goirsdk.EmulatorOptions{Speed: *speed, Loop: *loop})
+	if err != nil {
+		return err
+	}
+	defer emu.Close()
+
+	if *simStatus {
+		closeStatus, err := serveSimStatus()
+		if err != nil {
+			return err
+		}
+		defer closeStatus()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err = emu.Run(ctx)
+	if err == context.Canceled {
+		return nil
+	}
+	return err
+}
+
+// serveSimStatus answers on the address of goirsdk.SimStatusUrl that the
+// simulator runs
+func serveSimStatus() (func(), error) {
+	u, err := url.Parse(goirsdk.SimStatusUrl)
+	if err != nil {
+		return nil, err
+	}
+	l, err := net.Listen("tcp", u.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serve sim status: %v", err)
+	}
+
+	handler := &goirsdk.SimStatusHandler{}
+	handler.SetRunning(true)
+	server := &http.Server{Handler: handler}
+	go server.Serve(l)
+	return func() { server.Close() }, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ESilva15/goirsdk"
+)
+
+// typeNames maps the names accepted by -types to the irsdk types
+var typeNames = map[string]int32{
+	"char":     goirsdk.IRSDK_char,
+	"bool":     goirsdk.IRSDK_bool,
+	"int":      goirsdk.IRSDK_int,
+	"bitfield": goirsdk.IRSDK_bitField,
+	"float":    goirsdk.IRSDK_float,
+	"double":   goirsdk.IRSDK_double,
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	in := fs.String("in", "", "source .csv file")
+	out := fs.String("out", "", "destination .ibt file")
+	sessionInfo := fs.String("session-info", "", "session info .yaml file to embed")
+	types := fs.String("types", "", "comma separated Name=type pairs (char, bool, int, bitfield, float, double)")
+	rate := fs.Int("rate", 60, "tick rate of the data in Hz")
+	noUnits := fs.Bool("no-units", false, "the CSV has no units row")
+	fs.Parse(args)
+
+	if *in == "" || *out == "" || *sessionInfo == "" {
+		return fmt.Errorf("-in, -out and -session-info are required")
+	}
+
+	varTypes, err := parseTypes(*types)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.ReadFile(*sessionInfo)
+	if err != nil {
+		return fmt.Errorf("failed to read session info: %v", err)
+	}
+
+	src, err := os.Open(*in)
+	if err != nil {
+		return fmt.Errorf("failed to open CSV file: %v", err)
+	}
+	defer src.Close()
+
+	dst, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer dst.Close()
+
+	return goirsdk.ImportCSV(dst, bufio.NewReader(src), info, goirsdk.CSVImportOptions{
+		Types:      varTypes,
+		TickRate:   int32(*rate),
+		NoUnitsRow: *noUnits,
+	})
+}
+
+// parseTypes parses a comma separated list of Name=type pairs
+func parseTypes(s string) (map[string]int32, error) {
+	types := make(map[string]int32)
+	if s == "" {
+		return types, nil
+	}
+
+	for _, field := range strings.Split(s, ",") {
+		name, typeName, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			return nil, fmt.Errorf("type %q is not in the Name=type form", field)
+		}
+		t, ok := typeNames[strings.ToLower(typeName)]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %q for %s", typeName, name)
+		}
+		types[name] = t
+	}
+	return types, nil
+}
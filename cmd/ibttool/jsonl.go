@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ESilva15/goirsdk"
+)
+
+func runJSONLines(args []string) error {
+	fs := flag.NewFlagSet("jsonl", flag.ExitOnError)
+	in := fs.String("in", "", "source .ibt file")
+	out := fs.String("out", "-", "destination .jsonl file, - for stdout")
+	vars := fs.String("vars", "", "comma separated variables to export, all by default")
+	varsFile := fs.String("vars-file", "", "file with one variable to export per line")
+	precision := fs.Int("precision", 0, "digits after the decimal point, shortest form by default")
+	bitfields := fs.Bool("bitfields", false, "add the decoded flags of the bitfield variables")
+	sessionInfo := fs.String("session-info", "", "also write the session info as JSON to this file")
+	fs.Parse(args)
+
+	if *in == "" {
+		return fmt.Errorf("-in is required")
+	}
+
+	names, err := parseNames(*vars, *varsFile)
+	if err != nil {
+		return err
+	}
+
+	ibt, file, err := openIBT(*in)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	defer ibt.Close()
+
+	if *sessionInfo != "" {
+		si, err := os.Create(*sessionInfo)
+		if err != nil {
+			return fmt.Errorf("failed to create session info file: %v", err)
+		}
+		defer si.Close()
+
+		err = ibt.ExportSessionInfoJSON(si)
+		if err != nil {
+			return err
+		}
+	}
+
+	dst, closeDst, err := createOutput(*out)
+	if err != nil {
+		return err
+	}
+	defer closeDst()
+
+	w := bufio.NewWriter(dst)
+	err = ibt.ExportJSONLines(w, goirsdk.JSONLinesOptions{
+		Vars:      names,
+		Precision: *precision,
+		Bitfields: *bitfields,
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
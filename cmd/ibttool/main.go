@@ -0,0 +1,58 @@
+// ibttool groups the command line utilities to work with .ibt files
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+type command struct {
+	Name  string
+	Usage string
+	Run   func(args []string) error
+}
+
+var commands = []command{
+	{"trim", "extract a tick, session time, lap or session range into a new .ibt", runTrim},
+	{"subset", "rewrite an .ibt keeping only the chosen variables", runSubset},
+	{"merge", "join consecutive .ibt files of the same subsession", runMerge},
+	{"anonymize", "strip the identifying data of an .ibt before sharing it", runAnonymize},
+	{"import", "build an .ibt out of a CSV file and a session info .yaml", runImport},
+	{"compress", "compress an .ibt into a seekable .ibz, or back with -d", runCompress},
+	{"csv", "export the frames of an .ibt as CSV", runCSV},
+	{"motec", "convert an .ibt into MoTeC i2 .ld/.ldx files", runMoTeC},
+	{"jsonl", "export the frames of an .ibt as JSON Lines", runJSONLines},
+	{"parquet", "export an .ibt as a Parquet file", runParquet},
+	{"arrow", "export an .ibt as an Arrow IPC file", runArrow},
+	{"relay", "stream the live session, or an .ibt, to relay clients over TCP", runRelay},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: ibttool <command> [flags]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.Name, c.Usage)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, c := range commands {
+		if c.Name != os.Args[1] {
+			continue
+		}
+
+		err := c.Run(os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ibttool %s: %v\n", c.Name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	usage()
+	os.Exit(2)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ESilva15/goirsdk"
+)
+
+func runMerge(args []string) error {
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	out := fs.String("out", "", "destination .ibt file")
+	fs.Parse(args)
+
+	if *out == "" || fs.NArg() < 2 {
+		return fmt.Errorf("usage: ibttool merge -out merged.ibt a.ibt b.ibt [...]")
+	}
+
+	var sources []*goirsdk.IBT
+	for _, path := range fs.Args() {
+		ibt, file, err := openIBT(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		defer ibt.Close()
+
+		sources = append(sources, ibt)
+	}
+
+	// Put the files in recording order
+	sort.SliceStable(sources, func(a, b int) bool {
+		sa, sb := sources[a].SubHeaders, sources[b].SubHeaders
+		if sa.StartDate != sb.StartDate {
+			return sa.StartDate < sb.StartDate
+		}
+		return sa.StartTime < sb.StartTime
+	})
+
+	dst, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer dst.Close()
+
+	return goirsdk.Merge(dst, sources...)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ESilva15/goirsdk"
+)
+
+func runMoTeC(args []string) error {
+	fs := flag.NewFlagSet("motec", flag.ExitOnError)
+	in := fs.String("in", "", "source .ibt file")
+	out := fs.String("out", "", "destination .ld file, the .ldx is written next to it")
+	vars := fs.String("vars", "", "comma separated variables to export, all by default")
+	varsFile := fs.String("vars-file", "", "file with one variable to export per line")
+	fs.Parse(args)
+
+	if *in == "" || *out == "" {
+		return fmt.Errorf("both -in and -out are required")
+	}
+
+	names, err := parseNames(*vars, *varsFile)
+	if err != nil {
+		return err
+	}
+
+	ibt, file, err := openIBT(*in)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	defer ibt.Close()
+
+	ld, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer ld.Close()
+
+	ldx, err := os.Create(strings.TrimSuffix(*out, filepath.Ext(*out)) + ".ldx")
+	if err != nil {
+		return fmt.Errorf("failed to create ldx file: %v", err)
+	}
+	defer ldx.Close()
+
+	return ibt.ExportMoTeC(ld, ldx, goirsdk.MoTeCOptions{Vars: names})
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/ESilva15/goirsdk"
+	"github.com/ESilva15/goirsdk/wire"
+)
+
+func runRelay(args []string) error {
+	fs := flag.NewFlagSet("relay", flag.ExitOnError)
+	listen := fs.String("listen", ":32100", "address to accept the relay clients on")
+	in := fs.String("in", "", "relay an .ibt file instead of the live session")
+	keyframes := fs.Int("keyframes", wire.DefaultKeyframeInterval, "frames between the keyframes sent to the clients")
+	fs.Parse(args)
+
+	var ibt *goirsdk.IBT
+	var err error
+	if *in != "" {
+		var file *os.File
+		ibt, file, err = openIBT(*in)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+	} else {
+		ibt, err = goirsdk.Init(nil, "", "")
+		if err != nil {
+			return fmt.Errorf("failed to open the live session: %v", err)
+		}
+	}
+	defer ibt.Close()
+
+	server, err := goirsdk.NewRelayServer(ibt)
+	if err != nil {
+		return err
+	}
+	server.KeyframeInterval = *keyframes
+	defer server.Close()
+
+	l, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %v", err)
+	}
+	go server.Serve(l)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err = server.Run(ctx, time.Second)
+	if err == context.Canceled {
+		return nil
+	}
+	return err
+}
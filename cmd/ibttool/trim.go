@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/ESilva15/goirsdk"
+)
+
+func runTrim(args []string) error {
+	fs := flag.NewFlagSet("trim", flag.ExitOnError)
+	in := fs.String("in", "", "source .ibt file")
+	out := fs.String("out", "", "destination .ibt file")
+	ticks := fs.String("ticks", "", "tick range to keep, from:to (to is exclusive)")
+	sessionTime := fs.String("time", "", "session time range to keep in seconds, from:to")
+	laps := fs.String("laps", "", "comma separated laps to keep")
+	session := fs.Int("session", -1, "session number to keep")
+	fs.Parse(args)
+
+	if *in == "" || *out == "" {
+		return fmt.Errorf("both -in and -out are required")
+	}
+
+	var selectors []goirsdk.FrameSelector
+	if *ticks != "" {
+		from, to, err := splitRange(*ticks)
+		if err != nil {
+			return err
+		}
+		f, err := strconv.ParseInt(from, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid start tick: %v", err)
+		}
+		t, err := strconv.ParseInt(to, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid end tick: %v", err)
+		}
+		selectors = append(selectors, goirsdk.SelectTicks(int32(f), int32(t)))
+	}
+	if *sessionTime != "" {
+		from, to, err := splitRange(*sessionTime)
+		if err != nil {
+			return err
+		}
+		f, err := strconv.ParseFloat(from, 64)
+		if err != nil {
+			return fmt.Errorf("invalid start time: %v", err)
+		}
+		t, err := strconv.ParseFloat(to, 64)
+		if err != nil {
+			return fmt.Errorf("invalid end time: %v", err)
+		}
+		selectors = append(selectors, goirsdk.SelectSessionTime(f, t))
+	}
+	if *laps != "" {
+		values, err := parseInts(*laps)
+		if err != nil {
+			return err
+		}
+		selectors = append(selectors, goirsdk.SelectLaps(values...))
+	}
+	if *session >= 0 {
+		selectors = append(selectors, goirsdk.SelectSession(*session))
+	}
+	if len(selectors) == 0 {
+		return fmt.Errorf("at least one of -ticks, -time, -laps or -session is required")
+	}
+
+	ibt, file, err := openIBT(*in)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	defer ibt.Close()
+
+	dst, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer dst.Close()
+
+	return ibt.Trim(dst, goirsdk.SelectAll(selectors...))
+}
@@ -0,0 +1,206 @@
+// Package columnar reads telemetry files column by column and exports them as
+// Apache Arrow record batches, Arrow IPC files and Parquet files
+package columnar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/ESilva15/goirsdk"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+const (
+	defaultBatchSize = 60 * 60 // A minute of data at 60Hz
+)
+
+// Options configures the columnar reader
+type Options struct {
+	Vars      []string         // Vars to read, in this order. Empty reads every variable
+	BatchSize int              // BatchSize is the number of frames per record batch
+	Allocator memory.Allocator // Allocator for the Arrow buffers, Go's by default
+}
+
+// Reader turns the frames of a telemetry file into Arrow record batches
+type Reader struct {
+	ibt       *goirsdk.IBT
+	vars      []goirsdk.Var
+	schema    *arrow.Schema
+	builder   *array.RecordBuilder
+	batchSize int
+	tick      int32
+}
+
+// NewReader prepares a columnar reader over a telemetry file opened with
+// goirsdk.Init. Release the reader when done with it
+func NewReader(ibt *goirsdk.IBT, opts Options) (*Reader, error) {
+	vars, err := ibt.SelectVars(opts.Vars)
+	if err != nil {
+		return nil, err
+	}
+
+	mem := opts.Allocator
+	if mem == nil {
+		mem = memory.NewGoAllocator()
+	}
+
+	r := Reader{
+		ibt:       ibt,
+		vars:      vars,
+		schema:    Schema(ibt, vars),
+		batchSize: opts.BatchSize,
+	}
+	if r.batchSize <= 0 {
+		r.batchSize = defaultBatchSize
+	}
+	r.builder = array.NewRecordBuilder(mem, r.schema)
+
+	return &r, nil
+}
+
+// Schema returns the Arrow schema of the record batches
+func (r *Reader) Schema() *arrow.Schema {
+	return r.schema
+}
+
+// Next reads up to BatchSize frames into a record batch, returning io.EOF once
+// every frame was read. The caller must Release the record batch
+func (r *Reader) Next() (arrow.RecordBatch, error) {
+	rows := 0
+	for ; rows < r.batchSize; rows++ {
+		frame, err := r.ibt.ReadFrame(r.tick)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read frame %d: %v", r.tick, err)
+		}
+
+		for k, v := range r.vars {
+			appendVar(r.builder.Field(k), v, frame)
+		}
+		r.tick++
+	}
+
+	if rows == 0 {
+		return nil, io.EOF
+	}
+	return r.builder.NewRecordBatch(), nil
+}
+
+// Release frees the buffers held by the reader
+func (r *Reader) Release() {
+	r.builder.Release()
+}
+
+// Schema derives the Arrow schema of a set of variables. Arrays become fixed
+// size lists, the units and descriptions go in the fields' metadata and the
+// session identifiers in the schema's metadata
+func Schema(ibt *goirsdk.IBT, vars []goirsdk.Var) *arrow.Schema {
+	fields := make([]arrow.Field, len(vars))
+	for k, v := range vars {
+		fields[k] = arrow.Field{
+			Name: v.Name,
+			Type: arrowType(v),
+			Metadata: arrow.NewMetadata(
+				[]string{"unit", "description", "irsdk_type"},
+				[]string{v.Unit, v.Description, goirsdk.VarTypes[int(v.Type)].Name},
+			),
+		}
+	}
+
+	keys, values := sessionMetadata(ibt)
+	metadata := arrow.NewMetadata(keys, values)
+	return arrow.NewSchema(fields, &metadata)
+}
+
+// sessionMetadata returns the key/value pairs describing the session
+func sessionMetadata(ibt *goirsdk.IBT) ([]string, []string) {
+	weekend := ibt.SessionInfo.WeekendInfo
+	carID := 0
+	for _, d := range ibt.SessionInfo.DriverInfo.Drivers {
+		if d.CarIdx == ibt.SessionInfo.DriverInfo.DriverCarIdx {
+			carID = d.CarID
+		}
+	}
+
+	keys := []string{"TrackID", "TrackName", "CarID", "SeriesID", "SessionID",
+		"SubSessionID", "TickRate", "StartDate"}
+	values := []string{
+		strconv.Itoa(weekend.TrackID),
+		weekend.TrackName,
+		strconv.Itoa(carID),
+		strconv.Itoa(weekend.SeriesID),
+		strconv.Itoa(weekend.SessionID),
+		strconv.Itoa(weekend.SubSessionID),
+		strconv.Itoa(int(ibt.Headers.TickRate)),
+		strconv.FormatInt(ibt.SubHeaders.StartDate, 10),
+	}
+	return keys, values
+}
+
+// arrowType maps an irsdk variable type to its Arrow type
+func arrowType(v goirsdk.Var) arrow.DataType {
+	var dt arrow.DataType
+	switch v.Type {
+	case goirsdk.IRSDK_char:
+		// Char arrays hold strings
+		return arrow.BinaryTypes.String
+	case goirsdk.IRSDK_bool:
+		dt = arrow.FixedWidthTypes.Boolean
+	case goirsdk.IRSDK_int:
+		dt = arrow.PrimitiveTypes.Int32
+	case goirsdk.IRSDK_bitField:
+		dt = arrow.PrimitiveTypes.Uint32
+	case goirsdk.IRSDK_float:
+		dt = arrow.PrimitiveTypes.Float32
+	case goirsdk.IRSDK_double:
+		dt = arrow.PrimitiveTypes.Float64
+	}
+
+	if v.Count > 1 {
+		return arrow.FixedSizeListOf(v.Count, dt)
+	}
+	return dt
+}
+
+// appendVar appends the value of a variable in a frame to its column builder
+func appendVar(b array.Builder, v goirsdk.Var, frame []byte) {
+	if v.Type == goirsdk.IRSDK_char {
+		raw := frame[v.Offset : v.Offset+v.Count]
+		if end := bytes.IndexByte(raw, 0); end >= 0 {
+			raw = raw[:end]
+		}
+		b.(*array.StringBuilder).Append(string(raw))
+		return
+	}
+
+	if v.Count > 1 {
+		lb := b.(*array.FixedSizeListBuilder)
+		lb.Append(true)
+		b = lb.ValueBuilder()
+	}
+
+	size := int32(goirsdk.VarTypes[int(v.Type)].Size)
+	for entry := int32(0); entry < v.Count; entry++ {
+		raw := frame[v.Offset+entry*size : v.Offset+(entry+1)*size]
+		switch v.Type {
+		case goirsdk.IRSDK_bool:
+			b.(*array.BooleanBuilder).Append(raw[0] > 0)
+		case goirsdk.IRSDK_int:
+			b.(*array.Int32Builder).Append(int32(binary.LittleEndian.Uint32(raw)))
+		case goirsdk.IRSDK_bitField:
+			b.(*array.Uint32Builder).Append(binary.LittleEndian.Uint32(raw))
+		case goirsdk.IRSDK_float:
+			b.(*array.Float32Builder).Append(math.Float32frombits(binary.LittleEndian.Uint32(raw)))
+		case goirsdk.IRSDK_double:
+			b.(*array.Float64Builder).Append(math.Float64frombits(binary.LittleEndian.Uint64(raw)))
+		}
+	}
+}
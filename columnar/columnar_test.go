@@ -0,0 +1,170 @@
+package columnar
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ESilva15/goirsdk"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+const testSessionInfo = `---
+WeekendInfo:
+ TrackName: okayama full
+ TrackID: 166
+ SubSessionID: 12345
+DriverInfo:
+ DriverCarIdx: 0
+ Drivers:
+ - CarIdx: 0
+   CarID: 67
+...
+`
+
+// openTestIBT writes a small telemetry file and opens it with goirsdk.Init
+func openTestIBT(t *testing.T, frames int) *goirsdk.IBT {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.ibt")
+	dst, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer dst.Close()
+
+	vars := []goirsdk.Var{
+		{Type: goirsdk.IRSDK_double, Offset: 0, Count: 1, Name: "SessionTime", Unit: "s"},
+		{Type: goirsdk.IRSDK_float, Offset: 8, Count: 1, Name: "Speed", Unit: "m/s"},
+		{Type: goirsdk.IRSDK_int, Offset: 12, Count: 2, Name: "CarIdxLap"},
+	}
+	headers := goirsdk.TelemetryHeaders{Version: 2, TickRate: 60, BufLen: 20}
+	w, err := goirsdk.NewIBTWriter(dst, headers, goirsdk.DiskSubHeader{}, vars, []byte(testSessionInfo))
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	for tick := 0; tick < frames; tick++ {
+		frame := make([]byte, 20)
+		binary.LittleEndian.PutUint64(frame[0:], math.Float64bits(float64(tick)/60))
+		binary.LittleEndian.PutUint32(frame[8:], math.Float32bits(float32(tick)))
+		binary.LittleEndian.PutUint32(frame[12:], uint32(tick))
+		binary.LittleEndian.PutUint32(frame[16:], uint32(tick+1))
+		err = w.WriteFrame(frame)
+		if err != nil {
+			t.Fatalf("Failed to write frame: %v", err)
+		}
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("Failed to close writer: %v", err)
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open test file: %v", err)
+	}
+	t.Cleanup(func() { src.Close() })
+
+	ibt, err := goirsdk.Init(src, "", "")
+	if err != nil {
+		t.Fatalf("Failed to init: %v", err)
+	}
+	return ibt
+}
+
+// TestReader_Batches
+// Frames are split in batches and arrays become fixed size lists
+func TestReader_Batches(t *testing.T) {
+	// Arrange
+	ibt := openTestIBT(t, 25)
+	r, err := NewReader(ibt, Options{BatchSize: 10})
+	if err != nil {
+		t.Fatalf("Error creating reader: %v", err)
+	}
+	defer r.Release()
+
+	// Act
+	var rows []int64
+	var last arrow.RecordBatch
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Error reading batch: %v", err)
+		}
+		rows = append(rows, rec.NumRows())
+		if last != nil {
+			last.Release()
+		}
+		last = rec
+	}
+	defer last.Release()
+
+	// Assert
+	if len(rows) != 3 || rows[0] != 10 || rows[2] != 5 {
+		t.Fatalf("Expected batches of 10, 10 and 5 rows, got %v", rows)
+	}
+	laps := last.Column(2).(*array.FixedSizeList)
+	values := laps.ListValues().(*array.Int32)
+	if values.Value(8) != 24 || values.Value(9) != 25 {
+		t.Fatalf("Unexpected CarIdxLap values: %v", values)
+	}
+	if unit, _ := r.Schema().Field(1).Metadata.GetValue("unit"); unit != "m/s" {
+		t.Fatalf("Expected Speed in m/s, got %q", unit)
+	}
+}
+
+// TestWriteParquet_Metadata
+// The Parquet file holds every frame and the session identifiers
+func TestWriteParquet_Metadata(t *testing.T) {
+	// Arrange
+	ibt := openTestIBT(t, 25)
+	var out bytes.Buffer
+
+	// Act
+	err := WriteParquet(&out, ibt, Options{BatchSize: 10})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Error writing parquet: %v", err)
+	}
+	pf, err := file.NewParquetReader(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatalf("Error opening parquet: %v", err)
+	}
+	defer pf.Close()
+
+	if pf.NumRows() != 25 {
+		t.Fatalf("Expected 25 rows, got %d", pf.NumRows())
+	}
+	kv := pf.MetaData().KeyValueMetadata()
+	for key, expected := range map[string]string{"TrackID": "166", "CarID": "67", "SubSessionID": "12345"} {
+		if value := kv.FindValue(key); value == nil || *value != expected {
+			t.Fatalf("Expected %s=%s in the metadata", key, expected)
+		}
+	}
+
+	fr, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{}, memory.NewGoAllocator())
+	if err != nil {
+		t.Fatalf("Error reading parquet: %v", err)
+	}
+	tbl, err := fr.ReadTable(context.Background())
+	if err != nil {
+		t.Fatalf("Error reading table: %v", err)
+	}
+	defer tbl.Release()
+	if tbl.NumCols() != 3 {
+		t.Fatalf("Expected 3 columns, got %d", tbl.NumCols())
+	}
+}
@@ -0,0 +1,88 @@
+package columnar
+
+import (
+	"io"
+
+	"github.com/ESilva15/goirsdk"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// WriteArrow writes every frame of a telemetry file into w as an Arrow IPC
+// file, one record batch per BatchSize frames
+func WriteArrow(w io.Writer, ibt *goirsdk.IBT, opts Options) error {
+	r, err := NewReader(ibt, opts)
+	if err != nil {
+		return err
+	}
+	defer r.Release()
+
+	fw, err := ipc.NewFileWriter(w, ipc.WithSchema(r.Schema()))
+	if err != nil {
+		return err
+	}
+
+	err = eachBatch(r, fw.Write)
+	if err != nil {
+		fw.Close()
+		return err
+	}
+
+	return fw.Close()
+}
+
+// WriteParquet writes every frame of a telemetry file into w as a zstd
+// compressed Parquet file, one row group per BatchSize frames. The session
+// identifiers are stored as the file's key/value metadata
+func WriteParquet(w io.Writer, ibt *goirsdk.IBT, opts Options) error {
+	r, err := NewReader(ibt, opts)
+	if err != nil {
+		return err
+	}
+	defer r.Release()
+
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Zstd))
+	fw, err := pqarrow.NewFileWriter(r.Schema(), w, props, pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema()))
+	if err != nil {
+		return err
+	}
+
+	keys, values := sessionMetadata(ibt)
+	for k := range keys {
+		err = fw.AppendKeyValueMetadata(keys[k], values[k])
+		if err != nil {
+			fw.Close()
+			return err
+		}
+	}
+
+	err = eachBatch(r, fw.Write)
+	if err != nil {
+		fw.Close()
+		return err
+	}
+
+	return fw.Close()
+}
+
+// eachBatch hands every record batch of the reader to fn, releasing them after
+func eachBatch(r *Reader, fn func(arrow.RecordBatch) error) error {
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = fn(rec)
+		rec.Release()
+		if err != nil {
+			return err
+		}
+	}
+}
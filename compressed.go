@@ -0,0 +1,363 @@
+package goirsdk
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+)
+
+const (
+	compressedMagic       = "IBTZ"
+	compressedVersion     = 1
+	compressedHeaderSize  = 12        // Magic, version and chunk size
+	compressedFooterSize  = 24        // Size, chunk count, index offset and magic
+	compressedIndexSize   = 12        // Offset and length of a chunk
+	DefaultCompressedSize = 256 << 10 // DefaultCompressedSize is the default chunk size, 256KiB
+)
+
+// A compressed IBT file (.ibz) holds the IBT data split in chunks of the same
+// uncompressed size, each compressed on its own with gzip so any of them can
+// be read without the others. It is laid out as:
+//
+//	header: "IBTZ", version uint32, chunk size uint32
+//	chunks: gzip members, one per chunk
+//	index:  offset uint64 and compressed length uint32 of each chunk
+//	footer: uncompressed size uint64, chunk count uint32, index offset uint64, "IBTZ"
+//
+// Every integer is little endian.
+
+// chunkIndex locates a compressed chunk in the container
+type chunkIndex struct {
+	Offset uint64
+	Length uint32
+}
+
+// compressedFooter closes the container
+type compressedFooter struct {
+	Size        uint64
+	ChunkCount  uint32
+	IndexOffset uint64
+	Magic       [4]byte
+}
+
+// CompressedWriter compresses IBT data into a seekable container. Copy an IBT
+// file into it and Close it to write the index
+type CompressedWriter struct {
+	dst       io.Writer
+	level     int
+	chunkSize int
+	chunk     []byte
+	index     []chunkIndex
+	offset    uint64
+	size      uint64
+	closed    bool
+}
+
+// NewCompressedWriter starts a compressed container in dst. chunkSize is the
+// uncompressed size of each chunk, DefaultCompressedSize when 0. Smaller
+// chunks make random reads cheaper at the cost of compression ratio
+func NewCompressedWriter(dst io.Writer, chunkSize int) (*CompressedWriter, error) {
+	return NewCompressedWriterLevel(dst, chunkSize, gzip.DefaultCompression)
+}
+
+// NewCompressedWriterLevel is like NewCompressedWriter but takes a gzip
+// compression level
+func NewCompressedWriterLevel(dst io.Writer, chunkSize int, level int) (*CompressedWriter, error) {
+	if chunkSize == 0 {
+		chunkSize = DefaultCompressedSize
+	}
+	if chunkSize < 0 {
+		return nil, fmt.Errorf("invalid chunk size %d", chunkSize)
+	}
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		return nil, fmt.Errorf("invalid compression level %d", level)
+	}
+
+	var header [compressedHeaderSize]byte
+	copy(header[:4], compressedMagic)
+	binary.LittleEndian.PutUint32(header[4:], compressedVersion)
+	binary.LittleEndian.PutUint32(header[8:], uint32(chunkSize))
+	_, err := dst.Write(header[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to write compressed header: %v", err)
+	}
+
+	return &CompressedWriter{
+		dst:       dst,
+		level:     level,
+		chunkSize: chunkSize,
+		chunk:     make([]byte, 0, chunkSize),
+		offset:    compressedHeaderSize,
+	}, nil
+}
+
+// Write buffers p, compressing every chunk that gets filled
+func (w *CompressedWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, fmt.Errorf("the compressed writer is closed")
+	}
+
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), w.chunkSize-len(w.chunk))
+		w.chunk = append(w.chunk, p[:n]...)
+		p = p[n:]
+		written += n
+
+		if len(w.chunk) == w.chunkSize {
+			err := w.flushChunk()
+			if err != nil {
+				return written, err
+			}
+		}
+	}
+
+	return written, nil
+}
+
+// flushChunk compresses the buffered chunk into the container
+func (w *CompressedWriter) flushChunk() error {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, w.level)
+	if err != nil {
+		return err
+	}
+	_, err = zw.Write(w.chunk)
+	if err != nil {
+		return fmt.Errorf("failed to compress chunk %d: %v", len(w.index), err)
+	}
+	err = zw.Close()
+	if err != nil {
+		return fmt.Errorf("failed to compress chunk %d: %v", len(w.index), err)
+	}
+
+	_, err = w.dst.Write(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to write chunk %d: %v", len(w.index), err)
+	}
+
+	w.index = append(w.index, chunkIndex{Offset: w.offset, Length: uint32(buf.Len())})
+	w.offset += uint64(buf.Len())
+	w.size += uint64(len(w.chunk))
+	w.chunk = w.chunk[:0]
+	return nil
+}
+
+// Close compresses the last chunk and writes the index. It does not close the
+// underlying writer
+func (w *CompressedWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+
+	if len(w.chunk) > 0 {
+		err := w.flushChunk()
+		if err != nil {
+			return err
+		}
+	}
+	w.closed = true
+
+	var buf bytes.Buffer
+	for _, entry := range w.index {
+		binary.Write(&buf, binary.LittleEndian, entry)
+	}
+	footer := compressedFooter{
+		Size:        w.size,
+		ChunkCount:  uint32(len(w.index)),
+		IndexOffset: w.offset,
+	}
+	copy(footer.Magic[:], compressedMagic)
+	binary.Write(&buf, binary.LittleEndian, footer)
+
+	_, err := w.dst.Write(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to write compressed index: %v", err)
+	}
+	return nil
+}
+
+// CompressedReader gives random access to a compressed IBT container and
+// implements Reader, so it can be handed to Init like an *os.File. Only the
+// chunks holding the requested bytes get decompressed
+type CompressedReader struct {
+	src       io.ReaderAt
+	chunkSize int64
+	size      int64
+	index     []chunkIndex
+	offset    int64 // offset of the next Read
+
+	mu         sync.Mutex
+	cached     int // index of the cached chunk, -1 when empty
+	cacheChunk []byte
+}
+
+// IsCompressed tells if src holds a compressed IBT container
+func IsCompressed(src io.ReaderAt) bool {
+	var magic [4]byte
+	_, err := src.ReadAt(magic[:], 0)
+	return err == nil && string(magic[:]) == compressedMagic
+}
+
+// NewCompressedReader reads the index of the compressed container of the given
+// size held in src. Closing the reader closes src when it is an io.Closer
+func NewCompressedReader(src io.ReaderAt, size int64) (*CompressedReader, error) {
+	if size < compressedHeaderSize+compressedFooterSize {
+		return nil, fmt.Errorf("file too small to be a compressed IBT")
+	}
+
+	var header [compressedHeaderSize]byte
+	_, err := src.ReadAt(header[:], 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compressed header: %v", err)
+	}
+	if string(header[:4]) != compressedMagic {
+		return nil, fmt.Errorf("not a compressed IBT file")
+	}
+	if version := binary.LittleEndian.Uint32(header[4:]); version != compressedVersion {
+		return nil, fmt.Errorf("unsupported compressed IBT version %d", version)
+	}
+
+	var footerRaw [compressedFooterSize]byte
+	_, err = src.ReadAt(footerRaw[:], size-compressedFooterSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compressed footer: %v", err)
+	}
+	var footer compressedFooter
+	binary.Read(bytes.NewReader(footerRaw[:]), binary.LittleEndian, &footer)
+	if string(footer.Magic[:]) != compressedMagic {
+		return nil, fmt.Errorf("compressed IBT file is truncated")
+	}
+
+	// The header and footer aren't trusted, a corrupt file must not divide by
+	// zero or allocate more than it holds
+	chunkSize := int64(binary.LittleEndian.Uint32(header[8:]))
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("invalid compressed chunk size %d", chunkSize)
+	}
+	maxChunks := (size - compressedHeaderSize - compressedFooterSize) / compressedIndexSize
+	if int64(footer.ChunkCount) > maxChunks {
+		return nil, fmt.Errorf("compressed index of %d chunks doesn't fit in the file", footer.ChunkCount)
+	}
+	indexSize := int64(footer.ChunkCount) * compressedIndexSize
+	if footer.IndexOffset < compressedHeaderSize || footer.IndexOffset > uint64(size-compressedFooterSize-indexSize) {
+		return nil, fmt.Errorf("compressed index at %d doesn't fit in the file", footer.IndexOffset)
+	}
+	if footer.Size > uint64(footer.ChunkCount)*uint64(chunkSize) {
+		return nil, fmt.Errorf("compressed index of %d chunks can't hold %d bytes", footer.ChunkCount, footer.Size)
+	}
+
+	indexRaw := make([]byte, indexSize)
+	_, err = src.ReadAt(indexRaw, int64(footer.IndexOffset))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compressed index: %v", err)
+	}
+	index := make([]chunkIndex, footer.ChunkCount)
+	err = binary.Read(bytes.NewReader(indexRaw), binary.LittleEndian, index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse compressed index: %v", err)
+	}
+
+	return &CompressedReader{
+		src:       src,
+		chunkSize: chunkSize,
+		size:      int64(footer.Size),
+		index:     index,
+		cached:    -1,
+	}, nil
+}
+
+// Size returns the uncompressed size of the IBT data
+func (r *CompressedReader) Size() int64 {
+	return r.size
+}
+
+// ReadAt reads uncompressed bytes starting at off
+func (r *CompressedReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset %d", off)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for n < len(p) && off < r.size {
+		k := int(off / r.chunkSize)
+		chunk, err := r.chunk(k)
+		if err != nil {
+			return n, err
+		}
+
+		start := off - int64(k)*r.chunkSize
+		if start >= int64(len(chunk)) {
+			return n, fmt.Errorf("chunk %d is shorter than expected", k)
+		}
+		copied := copy(p[n:], chunk[start:])
+		n += copied
+		off += int64(copied)
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Read reads uncompressed bytes sequentially
+func (r *CompressedReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+
+	n, err := r.ReadAt(p, r.offset)
+	r.offset += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// Close closes the source of the container when it is an io.Closer
+func (r *CompressedReader) Close() error {
+	if c, ok := r.src.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// chunk returns the decompressed chunk k, keeping the last one around since
+// consecutive frames usually share it
+func (r *CompressedReader) chunk(k int) ([]byte, error) {
+	if k == r.cached {
+		return r.cacheChunk, nil
+	}
+	if k >= len(r.index) {
+		return nil, fmt.Errorf("chunk %d is missing from the index", k)
+	}
+
+	entry := r.index[k]
+	zr, err := gzip.NewReader(io.NewSectionReader(r.src, int64(entry.Offset), int64(entry.Length)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open chunk %d: %v", k, err)
+	}
+	defer zr.Close()
+
+	// A chunk is never larger than the chunk size, whatever the gzip stream says
+	buf := bytes.NewBuffer(r.cacheChunk[:0])
+	n, err := io.Copy(buf, io.LimitReader(zr, r.chunkSize+1))
+	if err == nil && n > r.chunkSize {
+		err = fmt.Errorf("larger than %d bytes", r.chunkSize)
+	}
+	if err != nil {
+		r.cached = -1
+		return nil, fmt.Errorf("failed to decompress chunk %d: %v", k, err)
+	}
+
+	r.cached = k
+	r.cacheChunk = buf.Bytes()
+	return r.cacheChunk, nil
+}
@@ -0,0 +1,170 @@
+package goirsdk
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// compressTestIBT compresses the file at path with the given chunk size
+func compressTestIBT(t *testing.T, path string, chunkSize int) []byte {
+	t.Helper()
+
+	src, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open test file: %v", err)
+	}
+	defer src.Close()
+
+	var dst bytes.Buffer
+	w, err := NewCompressedWriter(&dst, chunkSize)
+	if err != nil {
+		t.Fatalf("Failed to create compressed writer: %v", err)
+	}
+	_, err = io.Copy(w, src)
+	if err != nil {
+		t.Fatalf("Failed to compress test file: %v", err)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("Failed to close compressed writer: %v", err)
+	}
+	return dst.Bytes()
+}
+
+// TestCompressedReader_Init
+// A compressed file can be handed to Init and its frames read in any order
+func TestCompressedReader_Init(t *testing.T) {
+	// Arrange
+	path := writeTestIBT(t, 200)
+	original := openTestIBT(t, path)
+	compressed := compressTestIBT(t, path, 1000)
+
+	// Act
+	if !IsCompressed(bytes.NewReader(compressed)) {
+		t.Fatalf("Expected the container to be detected as compressed")
+	}
+	r, err := NewCompressedReader(bytes.NewReader(compressed), int64(len(compressed)))
+	if err != nil {
+		t.Fatalf("Failed to open compressed file: %v", err)
+	}
+	ibt, err := Init(r, "", "")
+	if err != nil {
+		t.Fatalf("Failed to init from compressed file: %v", err)
+	}
+
+	// Assert
+	if !cmp.Equal(original.Headers, ibt.Headers) {
+		t.Fatalf("Headers differ:\n%s", cmp.Diff(original.Headers, ibt.Headers))
+	}
+	if !cmp.Equal(original.SubHeaders, ibt.SubHeaders) {
+		t.Fatalf("Sub headers differ:\n%s", cmp.Diff(original.SubHeaders, ibt.SubHeaders))
+	}
+	for _, tick := range []int32{150, 3, 199, 0, 87} {
+		expected, err := original.ReadFrame(tick)
+		if err != nil {
+			t.Fatalf("Failed to read frame %d: %v", tick, err)
+		}
+		got, err := ibt.ReadFrame(tick)
+		if err != nil {
+			t.Fatalf("Failed to read compressed frame %d: %v", tick, err)
+		}
+		if !bytes.Equal(expected, got) {
+			t.Fatalf("Frame %d differs", tick)
+		}
+	}
+	_, err = ibt.ReadFrame(200)
+	if err != io.EOF {
+		t.Fatalf("Expected io.EOF past the last frame, got %v", err)
+	}
+}
+
+// TestCompressedReader_Read
+// Reading the container sequentially gives back the original file
+func TestCompressedReader_Read(t *testing.T) {
+	// Arrange
+	path := writeTestIBT(t, 50)
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	compressed := compressTestIBT(t, path, 64)
+
+	// Act
+	r, err := NewCompressedReader(bytes.NewReader(compressed), int64(len(compressed)))
+	if err != nil {
+		t.Fatalf("Failed to open compressed file: %v", err)
+	}
+	got, err := io.ReadAll(r)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to read compressed file: %v", err)
+	}
+	if r.Size() != int64(len(expected)) {
+		t.Fatalf("Expected size %d, got %d", len(expected), r.Size())
+	}
+	if !bytes.Equal(expected, got) {
+		t.Fatalf("Decompressed data differs from the original file")
+	}
+}
+
+// TestCompressedReader_Truncated
+// A container missing its index is rejected
+func TestCompressedReader_Truncated(t *testing.T) {
+	// Arrange
+	compressed := compressTestIBT(t, writeTestIBT(t, 10), 0)
+	truncated := compressed[:len(compressed)-5]
+
+	// Act
+	_, err := NewCompressedReader(bytes.NewReader(truncated), int64(len(truncated)))
+
+	// Assert
+	if err == nil {
+		t.Fatalf("Expected an error for a truncated container")
+	}
+}
+
+// TestCompressedReader_Corrupt
+// Containers whose header or footer hold impossible values are rejected
+func TestCompressedReader_Corrupt(t *testing.T) {
+	compressed := compressTestIBT(t, writeTestIBT(t, 10), 0)
+	footer := len(compressed) - compressedFooterSize
+
+	tests := []struct {
+		name   string
+		offset int // offset of the bytes overwritten
+		value  []byte
+	}{
+		{name: "Zero chunk size", offset: 8, value: []byte{0, 0, 0, 0}},
+		{name: "Huge chunk count", offset: footer + 8, value: []byte{0xff, 0xff, 0xff, 0xff}},
+		{name: "Zero chunk count", offset: footer + 8, value: []byte{0, 0, 0, 0}},
+		{name: "Index past the end", offset: footer + 12, value: []byte{0xff, 0xff, 0xff, 0x7f}},
+		{name: "Negative index offset", offset: footer + 12, value: []byte{0, 0, 0, 0, 0, 0, 0, 0x80}},
+		{name: "Huge index offset", offset: footer + 12, value: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{
+			name:   "Huge chunk count at a negative index offset",
+			offset: footer + 8,
+			value:  []byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0, 0, 0, 0, 0x80},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			corrupt := bytes.Clone(compressed)
+			copy(corrupt[test.offset:], test.value)
+
+			// Act
+			_, err := NewCompressedReader(bytes.NewReader(corrupt), int64(len(corrupt)))
+
+			// Assert
+			if err == nil {
+				t.Fatalf("Expected an error for a corrupt container")
+			}
+		})
+	}
+}
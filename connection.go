@@ -0,0 +1,228 @@
+package goirsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ConnectionState is the state of a Connection to the simulator
+type ConnectionState int
+
+const (
+	Disconnected ConnectionState = iota // Waiting for the simulator to start
+	Connected                           // Reading the data of a running session
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case Disconnected:
+		return "Disconnected"
+	case Connected:
+		return "Connected"
+	}
+	return fmt.Sprintf("ConnectionState(%d)", int(s))
+}
+
+var (
+	// ErrSimExited means iRacing cleared the connected bit of the Status
+	ErrSimExited = errors.New("the simulator exited")
+	// ErrStaleData means no new tick arrived within the stale timeout
+	ErrStaleData = errors.New("no new tick within the stale timeout")
+)
+
+// ConnectionEvent tells a Connection changed state
+type ConnectionEvent struct {
+	State ConnectionState
+	Err   error // Err is why the connection was lost, nil when connecting
+}
+
+// ConnectionOptions configures a Connection
+type ConnectionOptions struct {
+	// RetryInterval is the time between attempts to open the live data while
+	// disconnected, a second by default
+	RetryInterval time.Duration
+	// StaleTimeout is how long the tick count may stay the same before the
+	// simulator is considered gone, connTimeout seconds by default
+	StaleTimeout time.Duration
+	// OnEvent is called every time the connection changes state
+	OnEvent func(ConnectionEvent)
+	// Open opens the live data, OpenLiveSource by default
+	Open func() (TelemetrySource, error)
+	// Status, when set, is asked whether iRacing runs before opening the live
+	// data, which is skipped while it doesn't
+	Status *SimStatusClient
+}
+
+// Connection keeps an IBT connected to the live data of the simulator. It
+// waits for iRacing to start, notices when it exits or stops publishing ticks
+// and connects again, re-reading the headers and variables, when it's back
+type Connection struct {
+	opts        ConnectionOptions
+	ibt         *IBT
+	lastAttempt time.Time // When the live data was last opened
+	lastFrame   time.Time // When the last new tick was read
+	sim         SimStatus // Status of the simulator while disconnected
+}
+
+// NewConnection creates a disconnected Connection, it connects on Update
+func NewConnection(opts ConnectionOptions) *Connection {
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = time.Second
+	}
+	if opts.StaleTimeout <= 0 {
+		opts.StaleTimeout = time.Duration(connTimeout) * time.Second
+	}
+	if opts.Open == nil {
+		opts.Open = OpenLiveSource
+	}
+
+	return &Connection{opts: opts}
+}
+
+// State returns the state of the connection
+func (c *Connection) State() ConnectionState {
+	if c.ibt == nil {
+		return Disconnected
+	}
+	return Connected
+}
+
+// SimStatus tells whether the simulator isn't running, runs without the driver
+// in the car or runs with the driver on track
+func (c *Connection) SimStatus() SimStatus {
+	if c.ibt == nil {
+		return c.sim
+	}
+	if c.ibt.frame == nil {
+		return SimRunning
+	}
+	if onTrack, ok := c.ibt.frameNumber(c.ibt.frame, "IsOnTrack"); ok && onTrack != 0 {
+		return SimInCar
+	}
+	return SimRunning
+}
+
+// IBT returns the IBT reading the live data, nil while disconnected. A new
+// IBT is created on every reconnection
+func (c *Connection) IBT() *IBT {
+	return c.ibt
+}
+
+// Update reads the next tick like IBT.Update. While disconnected it tries to
+// connect, at most every RetryInterval, and returns TimedOut if the simulator
+// is not there within timeout
+func (c *Connection) Update(timeout time.Duration) (IRacingState, error) {
+	if c.ibt == nil {
+		if !c.connect(timeout) {
+			return TimedOut, nil
+		}
+	}
+
+	state, err := c.ibt.Update(timeout)
+	if errors.Is(err, ErrTornRead) {
+		return Failed, err
+	}
+	if err != nil {
+		c.disconnect(err)
+		return Failed, err
+	}
+
+	if !c.ibt.IsConnected() {
+		c.disconnect(ErrSimExited)
+		return TimedOut, nil
+	}
+
+	if state == TimedOut {
+		if time.Since(c.lastFrame) > c.opts.StaleTimeout {
+			c.disconnect(ErrStaleData)
+		}
+		return TimedOut, nil
+	}
+
+	c.lastFrame = time.Now()
+	return state, nil
+}
+
+// Run calls fn with the IBT after every new tick until ctx is done
+func (c *Connection) Run(ctx context.Context, fn func(*IBT)) error {
+	for ctx.Err() == nil {
+		state, _ := c.Update(c.opts.RetryInterval)
+		if state == Running {
+			fn(c.ibt)
+		}
+	}
+	return ctx.Err()
+}
+
+// Close disconnects without emitting an event
+func (c *Connection) Close() {
+	if c.ibt != nil {
+		c.ibt.Close()
+		c.ibt = nil
+	}
+}
+
+// connect waits up to timeout for the next attempt to open the live data
+func (c *Connection) connect(timeout time.Duration) bool {
+	wait := c.opts.RetryInterval - time.Since(c.lastAttempt)
+	if wait > timeout {
+		time.Sleep(timeout)
+		return false
+	}
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+	c.lastAttempt = time.Now()
+
+	if c.opts.Status != nil {
+		// The request has its own timeout, Update timeouts are often a tick
+		ctx, cancel := context.WithTimeout(context.Background(), simStatusTimeout)
+		status, err := c.opts.Status.Status(ctx)
+		cancel()
+		// An unexpected answer or a timeout doesn't prevent trying the memory map
+		if err == nil && status == SimNotRunning {
+			c.sim = SimNotRunning
+			return false
+		}
+	}
+
+	src, err := c.opts.Open()
+	if err != nil {
+		c.sim = SimNotRunning
+		return false
+	}
+
+	ibt, err := InitSource(src, "", "")
+	if err != nil {
+		src.Close()
+		return false
+	}
+	if !ibt.IsConnected() {
+		ibt.Close()
+		c.sim = SimRunning
+		return false
+	}
+
+	c.ibt = ibt
+	c.lastFrame = time.Now()
+	c.emit(ConnectionEvent{State: Connected})
+	return true
+}
+
+// disconnect drops the IBT and emits why
+func (c *Connection) disconnect(reason error) {
+	c.Close()
+	c.sim = SimRunning
+	if reason == ErrSimExited {
+		c.sim = SimNotRunning
+	}
+	c.emit(ConnectionEvent{State: Disconnected, Err: reason})
+}
+
+func (c *Connection) emit(e ConnectionEvent) {
+	if c.opts.OnEvent != nil {
+		c.opts.OnEvent(e)
+	}
+}
@@ -0,0 +1,99 @@
+//go:build linux && cgo
+// +build linux,cgo
+
+package goirsdk
+
+import (
+	"testing"
+	"time"
+)
+
+// TestConnection_Reconnect
+// The connection waits for the simulator, notices it exiting and reconnects
+// when it comes back
+func TestConnection_Reconnect(t *testing.T) {
+	// Arrange
+	var events []ConnectionEvent
+	conn := NewConnection(ConnectionOptions{
+		RetryInterval: time.Millisecond,
+		OnEvent:       func(e ConnectionEvent) { events = append(events, e) },
+	})
+	defer conn.Close()
+	source := openTestIBT(t, writeTestIBT(t, 100))
+	start := func() *Emulator {
+		emu, err := NewEmulator(source, EmulatorOptions{})
+		if err != nil {
+			t.Fatalf("Failed to start emulator: %v", err)
+		}
+		err = emu.Step()
+		if err != nil {
+			t.Fatalf("Failed to publish frame: %v", err)
+		}
+		return emu
+	}
+
+	// Act
+	waiting, _ := conn.Update(5 * time.Millisecond)
+	emu := start()
+	first, _ := conn.Update(5 * time.Millisecond)
+	firstState := conn.State()
+	emu.Close()
+	gone, _ := conn.Update(5 * time.Millisecond)
+	goneState := conn.State()
+	emu = start()
+	defer emu.Close()
+	again, _ := conn.Update(5 * time.Millisecond)
+
+	// Assert
+	if waiting != TimedOut || first != Running || gone != TimedOut || again != Running {
+		t.Fatalf("Unexpected states %v, %v, %v and %v", waiting, first, gone, again)
+	}
+	if firstState != Connected || goneState != Disconnected || conn.State() != Connected {
+		t.Fatalf("Unexpected connection states %v, %v and %v", firstState, goneState, conn.State())
+	}
+	expected := []ConnectionEvent{
+		{State: Connected},
+		{State: Disconnected, Err: ErrSimExited},
+		{State: Connected},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, events)
+	}
+	for k := range expected {
+		if events[k] != expected[k] {
+			t.Fatalf("Expected events %v, got %v", expected, events)
+		}
+	}
+	if conn.IBT().Vars.Tick != 1 {
+		t.Fatalf("Expected the first tick of the new session, got %d", conn.IBT().Vars.Tick)
+	}
+}
+
+// TestConnection_Stale
+// A session whose tick count stops moving is dropped after StaleTimeout
+func TestConnection_Stale(t *testing.T) {
+	// Arrange
+	var events []ConnectionEvent
+	conn := NewConnection(ConnectionOptions{
+		StaleTimeout: 10 * time.Millisecond,
+		OnEvent:      func(e ConnectionEvent) { events = append(events, e) },
+	})
+	defer conn.Close()
+	emu, _ := startTestEmulator(t, 10, EmulatorOptions{})
+	emu.Step()
+
+	// Act
+	conn.Update(time.Millisecond)
+	deadline := time.Now().Add(time.Second)
+	for conn.State() == Connected && time.Now().Before(deadline) {
+		conn.Update(time.Millisecond)
+	}
+
+	// Assert
+	if conn.State() != Disconnected {
+		t.Fatalf("Expected the stale session to be dropped")
+	}
+	if len(events) != 2 || events[1].Err != ErrStaleData {
+		t.Fatalf("Expected a stale disconnection, got %v", events)
+	}
+}
@@ -0,0 +1,164 @@
+package goirsdk
+
+import (
+	"encoding/binary"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// CSVOptions configures the CSV export of telemetry frames
+type CSVOptions struct {
+	Vars []string // Vars to export, in this order. Empty exports every variable
+	Rate int      // Rate in Hz of the output, a divisor of the TickRate. 0 keeps every frame
+
+	// TypesRow adds a third header row with the irsdk type of every column,
+	// irsdk_float, so ImportCSV gives the variables back their types
+	TypesRow bool
+}
+
+// CSVExporter streams telemetry frames as CSV rows. The first two rows hold
+// the column names and units, array variables are expanded into one column per
+// entry, CarIdxLap_0..CarIdxLap_63
+type CSVExporter struct {
+	w      *csv.Writer
+	vars   []Var
+	step   int
+	frames int
+	row    []string
+}
+
+// NewCSVExporter writes the header rows for the variables of i into w. Frames
+// are kept one in TickRate/Rate, so Rate must divide the TickRate
+func NewCSVExporter(w io.Writer, i *IBT, opts CSVOptions) (*CSVExporter, error) {
+	vars, err := i.SelectVars(opts.Vars)
+	if err != nil {
+		return nil, err
+	}
+	tickRate := int(i.Headers.TickRate)
+	if opts.Rate < 0 || (opts.Rate > 0 && (opts.Rate > tickRate || tickRate%opts.Rate != 0)) {
+		return nil, fmt.Errorf("rate %dHz doesn't divide the %dHz tick rate", opts.Rate, tickRate)
+	}
+
+	e := CSVExporter{
+		w:    csv.NewWriter(w),
+		vars: vars,
+		step: 1,
+	}
+	if opts.Rate > 0 {
+		e.step = tickRate / opts.Rate
+	}
+
+	var names, units, types []string
+	for _, v := range vars {
+		for _, name := range columnNames(v) {
+			names = append(names, name)
+			units = append(units, v.Unit)
+			types = append(types, VarTypes[int(v.Type)].Name)
+		}
+	}
+	e.row = make([]string, len(names))
+
+	err = e.w.Write(names)
+	if err != nil {
+		return nil, err
+	}
+	err = e.w.Write(units)
+	if err != nil {
+		return nil, err
+	}
+	if opts.TypesRow {
+		err = e.w.Write(types)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &e, nil
+}
+
+// WriteFrame writes a data frame as a CSV row, unless downsampling skips it
+func (e *CSVExporter) WriteFrame(frame []byte) error {
+	e.frames++
+	if (e.frames-1)%e.step != 0 {
+		return nil
+	}
+
+	col := 0
+	for _, v := range e.vars {
+		for entry := int32(0); entry < v.Count; entry++ {
+			e.row[col] = formatCell(v, frame, entry)
+			col++
+		}
+	}
+
+	return e.w.Write(e.row)
+}
+
+// Flush writes any buffered rows to the underlying writer
+func (e *CSVExporter) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// ExportCSV streams every frame of a telemetry file into w as CSV, one frame
+// is held in memory at a time
+func (i *IBT) ExportCSV(w io.Writer, opts CSVOptions) error {
+	e, err := NewCSVExporter(w, i, opts)
+	if err != nil {
+		return err
+	}
+
+	err = i.eachFrame(func(_ int32, frame []byte) error {
+		return e.WriteFrame(frame)
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.Flush()
+}
+
+// columnNames returns the column names of a variable, arrays get one column
+// per entry suffixed with its index
+func columnNames(v Var) []string {
+	if v.Count == 1 {
+		return []string{v.Name}
+	}
+
+	names := make([]string, v.Count)
+	for entry := range names {
+		names[entry] = fmt.Sprintf("%s_%d", v.Name, entry)
+	}
+	return names
+}
+
+// formatCell renders a single entry of a variable in a data frame. Booleans are
+// written as 0/1 and bitfields as plain integers so spreadsheets can use them
+func formatCell(v Var, frame []byte, entry int32) string {
+	size := int32(VarTypes[int(v.Type)].Size)
+	offset := v.Offset + entry*size
+	raw := frame[offset : offset+size]
+
+	switch v.Type {
+	case IRSDK_char:
+		return string(raw[0])
+	case IRSDK_bool:
+		if raw[0] > 0 {
+			return "1"
+		}
+		return "0"
+	case IRSDK_int:
+		return strconv.Itoa(int(int32(binary.LittleEndian.Uint32(raw))))
+	case IRSDK_bitField:
+		return strconv.FormatUint(uint64(binary.LittleEndian.Uint32(raw)), 10)
+	case IRSDK_float:
+		return strconv.FormatFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(raw))), 'g', -1, 32)
+	case IRSDK_double:
+		return strconv.FormatFloat(math.Float64frombits(binary.LittleEndian.Uint64(raw)), 'g', -1, 64)
+	}
+
+	return ""
+}
@@ -0,0 +1,66 @@
+package goirsdk
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestExportCSV_SelectedVarsDownsampled
+// Arrays are expanded into indexed columns, the units go in the second row
+// and the rate skips frames
+func TestExportCSV_SelectedVarsDownsampled(t *testing.T) {
+	// Arrange
+	src := openTestIBT(t, writeTestIBT(t, 5))
+	var out bytes.Buffer
+
+	// Act
+	err := src.ExportCSV(&out, CSVOptions{
+		Vars: []string{"Speed", "Gear", "IsOnTrack", "EngineWarnings", "CarIdxLap"},
+		Rate: 30,
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Error exporting: %v", err)
+	}
+	expected := "Speed,Gear,IsOnTrack,EngineWarnings,CarIdxLap_0,CarIdxLap_1,CarIdxLap_2,CarIdxLap_3\n" +
+		"m/s,,,irsdk_EngineWarnings,,,,\n" +
+		"0,-1,1,16,0,1,2,3\n" +
+		"2,-1,1,16,0,1,2,3\n" +
+		"4,-1,1,16,0,1,2,3\n"
+	if !cmp.Equal(expected, out.String()) {
+		t.Fatalf("Expected:\n%s\nGot:\n%s\n", expected, out.String())
+	}
+}
+
+// TestNewCSVExporter_Rate
+// Rates that don't divide the tick rate are refused instead of rounded
+func TestNewCSVExporter_Rate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rate  int
+		valid bool
+	}{
+		{name: "Every frame", rate: 0, valid: true},
+		{name: "Divisor", rate: 20, valid: true},
+		{name: "Tick rate", rate: 60, valid: true},
+		{name: "Not a divisor", rate: 25},
+		{name: "Above the tick rate", rate: 120},
+		{name: "Negative", rate: -10},
+	}
+
+	src := openTestIBT(t, writeTestIBT(t, 1))
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Act
+			_, err := NewCSVExporter(&bytes.Buffer{}, src, CSVOptions{Rate: test.rate})
+
+			// Assert
+			if (err == nil) != test.valid {
+				t.Fatalf("Expected valid %v for %dHz, got %v", test.valid, test.rate, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package goirsdk
+
+import (
+	"encoding/binary"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CSVImportOptions configures building an IBT file out of a CSV file
+type CSVImportOptions struct {
+	Types      map[string]int32 // Types of the variables, IRSDK_*. Read from the types row or inferred from the data when missing
+	TickRate   int32            // TickRate of the data, 60 by default
+	StartDate  int64            // StartDate for the disk sub header
+	NoUnitsRow bool             // NoUnitsRow tells the CSV has no units in its second row
+}
+
+// arrayColumn matches the indexed columns of array variables, CarIdxLap_12
+var arrayColumn = regexp.MustCompile(`^(.+)_(\d+)$`)
+
+// csvVar is a variable built out of one or more CSV columns
+type csvVar struct {
+	v       Var
+	columns []int
+}
+
+// ImportCSV builds an IBT file in dst out of a CSV with the layout written by
+// ExportCSV: a row with the variable names, a row with their units, the row
+// of types of CSVOptions.TypesRow when present and a row per frame. Indexed
+// columns (CarIdxLap_0..63) become array variables.
+// A variable missing from opts.Types takes the type of the types row, or is
+// inferred from its values without one: true/false are bools, integers are
+// ints (bitfields if their unit is an irsdk_ enum), numbers that don't fit a
+// float32 are doubles, other numbers floats and anything else chars. ExportCSV
+// writes bools as 0/1, they are only read back as bools with the types row.
+// The rows are held in memory to infer them
+func ImportCSV(dst io.WriterAt, src io.Reader, sessionInfo []byte, opts CSVImportOptions) error {
+	_, err := parseSessionInfo(sessionInfo, int32(len(sessionInfo)))
+	if err != nil {
+		return fmt.Errorf("invalid session info: %v", err)
+	}
+
+	r := csv.NewReader(src)
+	rows, err := r.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read CSV: %v", err)
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("the CSV has no header row")
+	}
+
+	names := rows[0]
+	units := make([]string, len(names))
+	rows = rows[1:]
+	if !opts.NoUnitsRow {
+		if len(rows) == 0 {
+			return fmt.Errorf("the CSV has no units row")
+		}
+		units = rows[0]
+		rows = rows[1:]
+	}
+
+	var columnTypes []int32
+	if len(rows) > 0 {
+		columnTypes = parseTypesRow(rows[0])
+		if columnTypes != nil {
+			rows = rows[1:]
+		}
+	}
+
+	vars, err := csvVars(names, units, columnTypes, rows, opts.Types)
+	if err != nil {
+		return err
+	}
+
+	layout := make([]Var, len(vars))
+	for k := range vars {
+		layout[k] = vars[k].v
+	}
+	layout, bufLen := packVars(layout)
+	for k := range vars {
+		vars[k].v = layout[k]
+	}
+
+	tickRate := opts.TickRate
+	if tickRate == 0 {
+		tickRate = 60
+	}
+	headers := TelemetryHeaders{Version: 2, Status: 1, TickRate: tickRate, BufLen: bufLen}
+	w, err := NewIBTWriter(dst, headers, DiskSubHeader{StartDate: opts.StartDate}, layout, sessionInfo)
+	if err != nil {
+		return err
+	}
+
+	for n, row := range rows {
+		frame := make([]byte, bufLen)
+		for _, cv := range vars {
+			for entry, col := range cv.columns {
+				err = encodeEntry(cv.v, frame, int32(entry), row[col])
+				if err != nil {
+					return fmt.Errorf("row %d, column %s: %v", n+1, names[col], err)
+				}
+			}
+		}
+
+		err = w.WriteFrame(frame)
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.Close()
+}
+
+// parseTypesRow reads the types row written by ExportCSV, nil when row holds
+// anything else than irsdk type names
+func parseTypesRow(row []string) []int32 {
+	types := make([]int32, len(row))
+	for col, name := range row {
+		found := false
+		for t, vt := range VarTypes {
+			if vt.Name == strings.TrimSpace(name) {
+				types[col] = int32(t)
+				found = true
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	return types
+}
+
+// csvVars groups the CSV columns into variables and settles their types, those
+// of types first, then those of the types row and the inferred ones last
+func csvVars(names []string, units []string, columnTypes []int32, rows [][]string, types map[string]int32) ([]csvVar, error) {
+	var vars []csvVar
+	index := make(map[string]int)
+	for col, name := range names {
+		// Indexed columns are entries of an array when they follow each other
+		if m := arrayColumn.FindStringSubmatch(name); m != nil {
+			entry, _ := strconv.Atoi(m[2])
+			if k, ok := index[m[1]]; ok && len(vars[k].columns) == entry {
+				vars[k].columns = append(vars[k].columns, col)
+				continue
+			}
+			if entry == 0 && col+1 < len(names) && names[col+1] == m[1]+"_1" {
+				name = m[1]
+			}
+		}
+
+		if _, ok := index[name]; ok {
+			return nil, fmt.Errorf("variable %s appears more than once", name)
+		}
+		index[name] = len(vars)
+		vars = append(vars, csvVar{
+			v:       Var{Name: name, Unit: units[col]},
+			columns: []int{col},
+		})
+	}
+
+	for k := range vars {
+		v := &vars[k].v
+		v.Count = int32(len(vars[k].columns))
+
+		if t, ok := types[v.Name]; ok {
+			if _, known := VarTypes[int(t)]; !known {
+				return nil, fmt.Errorf("variable %s has an unknown type %d", v.Name, t)
+			}
+			v.Type = t
+			continue
+		}
+		if columnTypes != nil {
+			v.Type = columnTypes[vars[k].columns[0]]
+			continue
+		}
+		v.Type = inferType(rows, vars[k].columns, v.Unit)
+	}
+
+	return vars, nil
+}
+
+// inferType picks the narrowest irsdk type that holds every value of columns
+func inferType(rows [][]string, columns []int, unit string) int32 {
+	isBool, isInt, isFloat := true, true, true
+	needsDouble := false
+	for _, row := range rows {
+		for _, col := range columns {
+			value := strings.TrimSpace(row[col])
+			if value != "true" && value != "false" {
+				isBool = false
+			}
+			if _, err := strconv.ParseInt(value, 10, 32); err != nil {
+				isInt = false
+			}
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				isFloat = false
+			} else if float64(float32(f)) != f {
+				needsDouble = true
+			}
+		}
+	}
+
+	switch {
+	case isBool && len(rows) > 0:
+		return IRSDK_bool
+	case isInt && strings.HasPrefix(unit, "irsdk_"):
+		return IRSDK_bitField
+	case isInt:
+		return IRSDK_int
+	case isFloat && needsDouble:
+		return IRSDK_double
+	case isFloat:
+		return IRSDK_float
+	}
+	return IRSDK_char
+}
+
+// encodeEntry writes a single value of a variable into a data frame
+func encodeEntry(v Var, frame []byte, entry int32, value string) error {
+	size := int32(VarTypes[int(v.Type)].Size)
+	raw := frame[v.Offset+entry*size : v.Offset+(entry+1)*size]
+	value = strings.TrimSpace(value)
+
+	switch v.Type {
+	case IRSDK_char:
+		if len(value) > 0 {
+			raw[0] = value[0]
+		}
+	case IRSDK_bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		if b {
+			raw[0] = 1
+		}
+	case IRSDK_int:
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return err
+		}
+		binary.LittleEndian.PutUint32(raw, uint32(int32(n)))
+	case IRSDK_bitField:
+		n, err := strconv.ParseUint(value, 0, 32)
+		if err != nil {
+			return err
+		}
+		binary.LittleEndian.PutUint32(raw, uint32(n))
+	case IRSDK_float:
+		f, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return err
+		}
+		binary.LittleEndian.PutUint32(raw, math.Float32bits(float32(f)))
+	case IRSDK_double:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		binary.LittleEndian.PutUint64(raw, math.Float64bits(f))
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package goirsdk
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// TestImportCSV_RoundTrip
+// Importing an exported CSV gives back the same variables and values
+func TestImportCSV_RoundTrip(t *testing.T) {
+	// Arrange
+	src := openTestIBT(t, writeTestIBT(t, 90))
+	var exported bytes.Buffer
+	err := src.ExportCSV(&exported, CSVOptions{})
+	if err != nil {
+		t.Fatalf("Error exporting: %v", err)
+	}
+
+	// Act
+	path := rewriteTestIBT(t, func(dst *os.File) error {
+		return ImportCSV(dst, bytes.NewReader(exported.Bytes()), []byte(testSessionInfo),
+			CSVImportOptions{
+				Types:     map[string]int32{"IsOnTrack": IRSDK_bool, "Speed": IRSDK_float},
+				StartDate: 1729371732,
+			})
+	})
+
+	// Assert
+	imported := openTestIBT(t, path)
+	expectedTypes := map[string]int32{}
+	for _, v := range testVars {
+		expectedTypes[v.Name] = v.Type
+	}
+	types := map[string]int32{}
+	for _, v := range imported.SortedVars() {
+		types[v.Name] = v.Type
+	}
+	if !cmp.Equal(expectedTypes, types) {
+		t.Fatalf("Expected types:\n%v\nGot:\n%v\n", expectedTypes, types)
+	}
+	if !cmp.Equal(src.SubHeaders, imported.SubHeaders) {
+		t.Fatalf("Expected:\n%s\nGot:\n%s\n", src.SubHeaders.ToString(), imported.SubHeaders.ToString())
+	}
+
+	var reexported bytes.Buffer
+	err = imported.ExportCSV(&reexported, CSVOptions{})
+	if err != nil {
+		t.Fatalf("Error exporting imported data: %v", err)
+	}
+	if !cmp.Equal(exported.String(), reexported.String()) {
+		t.Fatalf("Expected:\n%s\nGot:\n%s\n", exported.String(), reexported.String())
+	}
+}
+
+// TestImportCSV_TypesRow
+// With the types row the export is imported back with its types and no
+// overrides, bools written as 0/1 included
+func TestImportCSV_TypesRow(t *testing.T) {
+	// Arrange
+	src := openTestIBT(t, writeTestIBT(t, 30))
+	var exported bytes.Buffer
+	err := src.ExportCSV(&exported, CSVOptions{TypesRow: true})
+	if err != nil {
+		t.Fatalf("Error exporting: %v", err)
+	}
+
+	// Act
+	path := rewriteTestIBT(t, func(dst *os.File) error {
+		return ImportCSV(dst, bytes.NewReader(exported.Bytes()), []byte(testSessionInfo), CSVImportOptions{})
+	})
+
+	// Assert
+	imported := openTestIBT(t, path)
+	if diff := cmp.Diff(src.SortedVars(), imported.SortedVars(), cmpopts.IgnoreFields(Var{}, "Value", "Description")); diff != "" {
+		t.Fatalf("Variables mismatch (-want +got):\n%s", diff)
+	}
+	var reexported bytes.Buffer
+	err = imported.ExportCSV(&reexported, CSVOptions{TypesRow: true})
+	if err != nil {
+		t.Fatalf("Error exporting imported data: %v", err)
+	}
+	if diff := cmp.Diff(exported.String(), reexported.String()); diff != "" {
+		t.Fatalf("CSV mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestImportCSV_BadValue
+// A value that doesn't fit the requested type is reported with its row
+func TestImportCSV_BadValue(t *testing.T) {
+	csv := "Gear,Speed\n,m/s\n1,2.5\nnope,3\n"
+	dst, err := os.Create(filepath.Join(t.TempDir(), "bad.ibt"))
+	if err != nil {
+		t.Fatalf("Failed to create output: %v", err)
+	}
+	defer dst.Close()
+
+	err = ImportCSV(dst, strings.NewReader(csv), []byte(testSessionInfo),
+		CSVImportOptions{Types: map[string]int32{"Gear": IRSDK_int}})
+
+	if err == nil || !strings.Contains(err.Error(), "row 2, column Gear") {
+		t.Fatalf("Expected an error on row 2, got %v", err)
+	}
+}
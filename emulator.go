@@ -0,0 +1,265 @@
+//go:build linux && cgo
+// +build linux,cgo
+
+package goirsdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ESilva15/goirsdk/sharedMem"
+	"github.com/ESilva15/goirsdk/winutils"
+)
+
+const (
+	emulatorNumBuf    = 3  // iRacing rotates between 3 buffers
+	emulatorAlignment = 16 // Alignment of the data buffers in the memory map
+)
+
+// EmulatorOptions configures the live session emulator
+type EmulatorOptions struct {
+	// Speed multiplies the tick rate of the source, 1 publishes in real time.
+	// With 0 Run publishes the frames as fast as it can
+	Speed float64
+	// Loop restarts from the first frame once the last one is published
+	Loop bool
+}
+
+// Emulator publishes a telemetry file into shared memory with the layout of a
+// live iRacing session, so the live code paths can run on Linux. Init(nil, ...)
+// reads from it as it would from the simulator
+type Emulator struct {
+	src        *IBT
+	opts       EmulatorOptions
+	mem        *sharedMem.Memory
+	event      *sharedMem.Memory
+	headers    TelemetryHeaders
+	bufOffsets [emulatorNumBuf]int32
+	next       int32  // Next frame of the source to publish
+	tickCount  int32  // TickCount of the last published frame
+	signals    uint32 // Times the data valid event was signaled
+}
+
+// NewEmulator creates the memory map and data valid event of a live session
+// and fills them with the variables and session info of src. No frame is
+// published until Step or Run are called
+func NewEmulator(src *IBT, opts EmulatorOptions) (*Emulator, error) {
+	if src.source.Live() {
+		return nil, fmt.Errorf("the emulator needs a telemetry file as its source")
+	}
+
+	sessionInfo, err := src.rawSessionInfo()
+	if err != nil {
+		return nil, err
+	}
+	vars := src.SortedVars()
+
+	// The layout is: header, var headers, session info, data buffers
+	e := Emulator{src: src, opts: opts}
+	e.headers = TelemetryHeaders{
+		Version:           src.Headers.Version,
+		Status:            int32(stConnected),
+		TickRate:          src.Headers.TickRate,
+		SessionInfoUpdate: 1,
+		SessionInfoLength: int32(len(sessionInfo)),
+		NumVars:           int32(len(vars)),
+		VarHeaderOffset:   FileHeaderSize + SubHeaderSize,
+		NumBuf:            emulatorNumBuf,
+		BufLen:            src.Headers.BufLen,
+	}
+	e.headers.SessionInfoOffset = e.headers.VarHeaderOffset + e.headers.NumVars*VarHeaderSize
+
+	bufStride := align(e.headers.BufLen, emulatorAlignment)
+	start := align(e.headers.SessionInfoOffset+e.headers.SessionInfoLength, emulatorAlignment)
+	for k := range e.bufOffsets {
+		e.bufOffsets[k] = start + int32(k)*bufStride
+	}
+	e.headers.BufOffset = e.bufOffsets[0]
+	if end := e.bufOffsets[emulatorNumBuf-1] + bufStride; uint32(end) > fileMapSize {
+		return nil, fmt.Errorf("the session needs %d bytes, more than the %d of the memory map",
+			end, fileMapSize)
+	}
+
+	e.mem, err = sharedMem.Create(IRSDK_MEMMAPFILENAME, fileMapSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create memory map: %v", err)
+	}
+	e.event, err = sharedMem.Create(IRSDK_DATAVALIDEVENTNAME, winutils.EventSize)
+	if err != nil {
+		e.mem.Close()
+		return nil, fmt.Errorf("failed to create data valid event: %v", err)
+	}
+
+	// Leftovers of a previous session must not be taken for data
+	_, err = e.mem.WriteAt(make([]byte, fileMapSize), 0)
+	if err != nil {
+		e.Close()
+		return nil, fmt.Errorf("failed to clear memory map: %v", err)
+	}
+
+	for k, v := range vars {
+		raw, err := encodeVarHeader(v)
+		if err != nil {
+			e.Close()
+			return nil, err
+		}
+		_, err = e.mem.WriteAt(raw, int64(e.headers.VarHeaderOffset+int32(k)*VarHeaderSize))
+		if err != nil {
+			e.Close()
+			return nil, fmt.Errorf("failed to write variable header: %v", err)
+		}
+	}
+
+	_, err = e.mem.WriteAt(sessionInfo, int64(e.headers.SessionInfoOffset))
+	if err != nil {
+		e.Close()
+		return nil, fmt.Errorf("failed to write session info: %v", err)
+	}
+
+	err = e.writeHeader()
+	if err != nil {
+		e.Close()
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// writeHeader writes the header and the var buffers table
+func (e *Emulator) writeHeader() error {
+	buf := bytes.NewBuffer(make([]byte, 0, FileHeaderSize))
+	err := binary.Write(buf, binary.LittleEndian, &e.headers)
+	if err != nil {
+		return fmt.Errorf("unable to pack headers: %v", err)
+	}
+
+	raw := make([]byte, FileHeaderSize)
+	copy(raw, buf.Bytes())
+	for k, offset := range e.bufOffsets {
+		binary.LittleEndian.PutUint32(raw[varBufOffset+k*varBufSize+4:], uint32(offset))
+	}
+	// The tick counts are left alone, they belong to the published frames
+	for k := range e.bufOffsets {
+		entry := varBufOffset + k*varBufSize
+		tick, err := e.readInt32(int64(entry))
+		if err != nil {
+			return err
+		}
+		binary.LittleEndian.PutUint32(raw[entry:], uint32(tick))
+	}
+
+	_, err = e.mem.WriteAt(raw, 0)
+	if err != nil {
+		return fmt.Errorf("failed to write headers: %v", err)
+	}
+	return nil
+}
+
+// readInt32 reads a little endian int32 out of the memory map
+func (e *Emulator) readInt32(offset int64) (int32, error) {
+	var raw [4]byte
+	_, err := e.mem.ReadAt(raw[:], offset)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read memory map: %v", err)
+	}
+	return int32(binary.LittleEndian.Uint32(raw[:])), nil
+}
+
+// TickCount returns the TickCount of the last published frame, 0 before the
+// first one
+func (e *Emulator) TickCount() int32 {
+	return e.tickCount
+}
+
+// Step publishes the next frame of the source into the next var buffer and
+// signals the data valid event. It returns io.EOF after the last frame unless
+// the emulator loops
+func (e *Emulator) Step() error {
+	frame, err := e.src.ReadFrame(e.next)
+	if err == io.EOF && e.opts.Loop && e.next > 0 {
+		e.next = 0
+		frame, err = e.src.ReadFrame(e.next)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Like iRacing, TickCount starts at 1 and the buffers are used in turns
+	tickCount := e.tickCount + 1
+	k := int(tickCount % emulatorNumBuf)
+	_, err = e.mem.WriteAt(frame, int64(e.bufOffsets[k]))
+	if err != nil {
+		return fmt.Errorf("failed to write frame: %v", err)
+	}
+
+	var raw [4]byte
+	binary.LittleEndian.PutUint32(raw[:], uint32(tickCount))
+	_, err = e.mem.WriteAt(raw[:], int64(varBufOffset+k*varBufSize))
+	if err != nil {
+		return fmt.Errorf("failed to write tick count: %v", err)
+	}
+	e.tickCount = tickCount
+	e.next++
+
+	e.signals++
+	binary.LittleEndian.PutUint32(raw[:], e.signals)
+	_, err = e.event.WriteAt(raw[:], 0)
+	if err != nil {
+		return fmt.Errorf("failed to signal data valid event: %v", err)
+	}
+
+	return nil
+}
+
+// Run publishes the frames at the tick rate of the source times the emulator
+// speed until the last frame is published or ctx is done
+func (e *Emulator) Run(ctx context.Context) error {
+	rate := float64(e.headers.TickRate) * e.opts.Speed
+	start := time.Now()
+	for n := 0; ; n++ {
+		if rate > 0 {
+			deadline := start.Add(time.Duration(float64(n) / rate * float64(time.Second)))
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Until(deadline)):
+			}
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err := e.Step()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Close marks the session as disconnected and removes the memory map and
+// data valid event
+func (e *Emulator) Close() error {
+	var err error
+	if e.mem != nil {
+		e.headers.Status = 0
+		err = e.writeHeader()
+		e.mem.Close()
+		e.mem = nil
+	}
+	if e.event != nil {
+		e.event.Close()
+		e.event = nil
+	}
+	return err
+}
+
+// align rounds n up to a multiple of to
+func align(n int32, to int32) int32 {
+	return (n + to - 1) / to * to
+}
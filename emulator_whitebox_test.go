@@ -0,0 +1,241 @@
+//go:build linux && cgo
+// +build linux,cgo
+
+package goirsdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ESilva15/goirsdk/winutils"
+)
+
+// startTestEmulator publishes a synthetic telemetry file and connects to it
+func startTestEmulator(t *testing.T, frames int, opts EmulatorOptions) (*Emulator, *IBT) {
+	t.Helper()
+
+	source := openTestIBT(t, writeTestIBT(t, frames))
+	emu, err := NewEmulator(source, opts)
+	if err != nil {
+		t.Fatalf("Failed to start emulator: %v", err)
+	}
+	t.Cleanup(func() { emu.Close() })
+
+	live, err := Init(nil, "", "")
+	if err != nil {
+		t.Fatalf("Failed to connect to emulator: %v", err)
+	}
+	t.Cleanup(live.Close)
+
+	return emu, live
+}
+
+// TestEmulator_Update
+// The live branch of Update reads the latest frame published by the emulator
+func TestEmulator_Update(t *testing.T) {
+	// Arrange
+	emu, live := startTestEmulator(t, 10, EmulatorOptions{})
+
+	// Act
+	for k := 0; k < 4; k++ {
+		err := emu.Step()
+		if err != nil {
+			t.Fatalf("Failed to publish frame %d: %v", k, err)
+		}
+	}
+	_, err := live.Update(0)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+	if !live.IsConnected() {
+		t.Fatalf("Expected the emulated session to be connected")
+	}
+	if live.Headers.NumBuf != 3 {
+		t.Fatalf("Expected 3 buffers, got %d", live.Headers.NumBuf)
+	}
+	if live.SessionInfo.WeekendInfo.TrackID != 166 {
+		t.Fatalf("Expected TrackID 166, got %d", live.SessionInfo.WeekendInfo.TrackID)
+	}
+	if live.Vars.Tick != 4 {
+		t.Fatalf("Expected tick 4, got %d", live.Vars.Tick)
+	}
+	if got := live.Vars.Vars["Speed"].Value.(float32); got != 3 {
+		t.Fatalf("Expected the speed of the fourth frame, got %v", got)
+	}
+}
+
+// TestEmulator_UpdateTimeout
+// Update waits for a new tick and times out instead of decoding a stale frame
+func TestEmulator_UpdateTimeout(t *testing.T) {
+	// Arrange
+	emu, live := startTestEmulator(t, 10, EmulatorOptions{})
+
+	// Act
+	start := time.Now()
+	empty, _ := live.Update(20 * time.Millisecond)
+	waited := time.Since(start)
+	err := emu.Step()
+	if err != nil {
+		t.Fatalf("Failed to publish frame: %v", err)
+	}
+	fresh, _ := live.Update(20 * time.Millisecond)
+	stale, _ := live.Update(5 * time.Millisecond)
+
+	// Assert
+	if empty != TimedOut || fresh != Running || stale != TimedOut {
+		t.Fatalf("Expected TimedOut, Running and TimedOut, got %v, %v and %v", empty, fresh, stale)
+	}
+	if waited < 20*time.Millisecond {
+		t.Fatalf("Expected Update to wait for the timeout, returned after %v", waited)
+	}
+	if live.Vars.Tick != 1 {
+		t.Fatalf("Expected the data of the first tick to be kept, got tick %d", live.Vars.Tick)
+	}
+}
+
+// TestEmulator_Run
+// Run publishes every frame of the source and returns
+func TestEmulator_Run(t *testing.T) {
+	// Arrange
+	emu, _ := startTestEmulator(t, 5, EmulatorOptions{Speed: 100})
+
+	// Act
+	err := emu.Run(context.Background())
+
+	// Assert
+	if err != nil || emu.TickCount() != 5 {
+		t.Fatalf("Expected 5 frames without error, got %d and %v", emu.TickCount(), err)
+	}
+}
+
+// TestEmulator_RunLoop
+// A looping emulator keeps publishing until its context is done
+func TestEmulator_RunLoop(t *testing.T) {
+	// Arrange
+	emu, _ := startTestEmulator(t, 5, EmulatorOptions{Loop: true})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// Act
+	err := emu.Run(ctx)
+
+	// Assert
+	if err != context.DeadlineExceeded || emu.TickCount() <= 5 {
+		t.Fatalf("Expected the loop to run until cancelled, got %d and %v", emu.TickCount(), err)
+	}
+}
+
+// racingMemory publishes new frames right after the reader copies one, like
+// iRacing would if it wrapped around the buffers during the copy
+type racingMemory struct {
+	winutils.Reader
+	emu    *Emulator
+	bufLen int
+	races  int
+}
+
+func (m *racingMemory) ReadAt(p []byte, off int64) (int, error) {
+	n, err := m.Reader.ReadAt(p, off)
+	if len(p) == m.bufLen && m.races > 0 {
+		m.races--
+		for k := 0; k < emulatorNumBuf; k++ {
+			m.emu.Step()
+		}
+	}
+	return n, err
+}
+
+// TestLiveSource_TornRead
+// A frame overwritten while being copied is read again, up to maxReadTries
+func TestLiveSource_TornRead(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		races    int
+		expected error
+		retries  int64
+	}{
+		{"no race", 0, nil, 0},
+		{"one race", 1, nil, 1},
+		{"always racing", maxReadTries, ErrTornRead, maxReadTries - 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			emu, live := startTestEmulator(t, 100, EmulatorOptions{})
+			err := emu.Step()
+			if err != nil {
+				t.Fatalf("Failed to publish frame: %v", err)
+			}
+			src := live.source.(*liveSource)
+			src.mem = &racingMemory{
+				Reader: src.mem,
+				emu:    emu,
+				bufLen: int(live.Headers.BufLen),
+				races:  tc.races,
+			}
+
+			// Act
+			_, err = live.Update(0)
+
+			// Assert
+			if err != tc.expected {
+				t.Fatalf("Expected error %v, got %v", tc.expected, err)
+			}
+			if live.ReadRetries() != tc.retries {
+				t.Fatalf("Expected %d retries, got %d", tc.retries, live.ReadRetries())
+			}
+			if err == nil && live.Vars.Tick != emu.TickCount() {
+				t.Fatalf("Expected the latest tick %d, got %d", emu.TickCount(), live.Vars.Tick)
+			}
+		})
+	}
+}
+
+// TestEmulator_Stats
+// Ticks published between two Updates are reported as skipped
+func TestEmulator_Stats(t *testing.T) {
+	// Arrange
+	emu, live := startTestEmulator(t, 100, EmulatorOptions{})
+	step := func(n int) {
+		for k := 0; k < n; k++ {
+			err := emu.Step()
+			if err != nil {
+				t.Fatalf("Failed to publish frame: %v", err)
+			}
+		}
+	}
+
+	// Act
+	step(1)
+	live.Update(0)
+	step(4)
+	live.Update(0)
+	skipped := live.Vars.SkippedTicks
+	time.Sleep(2 * live.tickPeriod())
+	live.Update(0)
+	stats := live.Stats()
+
+	// Assert
+	if skipped != 3 {
+		t.Fatalf("Expected 3 skipped ticks, got %d", skipped)
+	}
+	// The last read is late for sure, a slow run can make the others late too
+	if stats.LateReads < 1 {
+		t.Fatalf("Expected a late read, got %d", stats.LateReads)
+	}
+	expected := UpdateStats{Frames: 2, SkippedTicks: 3, DroppedReads: 1, Timeouts: 1}
+	stats.DecodeTime, stats.MaxDecodeTime, stats.LateReads = 0, 0, 0
+	if stats != expected {
+		t.Fatalf("Expected stats %+v, got %+v", expected, stats)
+	}
+	if live.Stats().DropRate() != 0.6 {
+		t.Fatalf("Expected a drop rate of 0.6, got %v", live.Stats().DropRate())
+	}
+
+	live.ResetStats()
+	if live.Stats() != (UpdateStats{}) {
+		t.Fatalf("Expected the stats to be reset, got %+v", live.Stats())
+	}
+}
@@ -0,0 +1,174 @@
+package goirsdk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// IBTWriter produces a valid .ibt file out of a variable layout, a session
+// info string and a sequence of data frames. The headers and disk sub headers
+// are only final once Close is called, since they depend on the written frames
+type IBTWriter struct {
+	Headers    TelemetryHeaders // Headers that will be written to the file
+	SubHeaders DiskSubHeader    // SubHeaders, corrected as frames are written
+	dst        io.WriterAt
+	vars       []Var
+	laps       map[int]struct{}
+	closed     bool
+}
+
+// NewIBTWriter prepares dst to receive an IBT file.
+// headers -> provides Version, Status, TickRate, SessionInfoUpdate and BufLen,
+// the offsets are computed by the writer
+// sub -> provides the StartDate, the remaining fields are computed from the
+// written frames
+// vars -> the variable layout of every frame, offsets must fit in BufLen
+// sessionInfo -> the raw session info string
+func NewIBTWriter(dst io.WriterAt, headers TelemetryHeaders, sub DiskSubHeader,
+	vars []Var, sessionInfo []byte) (*IBTWriter, error) {
+	sorted := make([]Var, len(vars))
+	copy(sorted, vars)
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a].Offset < sorted[b].Offset })
+
+	for _, v := range sorted {
+		end := v.Offset + v.Count*int32(VarTypes[int(v.Type)].Size)
+		if v.Offset < 0 || end > headers.BufLen {
+			return nil, fmt.Errorf("variable %s does not fit in a %d bytes buffer", v.Name, headers.BufLen)
+		}
+	}
+
+	w := IBTWriter{
+		Headers: headers,
+		SubHeaders: DiskSubHeader{
+			StartDate: sub.StartDate,
+		},
+		dst:  dst,
+		vars: sorted,
+		laps: make(map[int]struct{}),
+	}
+
+	// The layout is: header, disk sub header, var headers, session info, data
+	w.Headers.NumBuf = 1
+	w.Headers.NumVars = int32(len(sorted))
+	w.Headers.VarHeaderOffset = FileHeaderSize + SubHeaderSize
+	w.Headers.SessionInfoOffset = w.Headers.VarHeaderOffset + w.Headers.NumVars*VarHeaderSize
+	w.Headers.SessionInfoLength = int32(len(sessionInfo))
+	w.Headers.BufOffset = w.Headers.SessionInfoOffset + w.Headers.SessionInfoLength
+
+	for k, v := range sorted {
+		raw, err := encodeVarHeader(v)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = dst.WriteAt(raw, int64(w.Headers.VarHeaderOffset+int32(k)*VarHeaderSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to write variable header: %v", err)
+		}
+	}
+
+	_, err := dst.WriteAt(sessionInfo, int64(w.Headers.SessionInfoOffset))
+	if err != nil {
+		return nil, fmt.Errorf("failed to write session info: %v", err)
+	}
+
+	return &w, nil
+}
+
+// WriteFrame appends a BufLen sized data frame to the file
+func (w *IBTWriter) WriteFrame(frame []byte) error {
+	if w.closed {
+		return fmt.Errorf("writer is closed")
+	}
+	if len(frame) != int(w.Headers.BufLen) {
+		return fmt.Errorf("frame has %d bytes, expected %d", len(frame), w.Headers.BufLen)
+	}
+
+	offset := int64(w.Headers.BufOffset) + int64(w.SubHeaders.RecordCount)*int64(w.Headers.BufLen)
+	_, err := w.dst.WriteAt(frame, offset)
+	if err != nil {
+		return fmt.Errorf("failed to write frame: %v", err)
+	}
+
+	// Keep the disk sub headers in line with what we wrote
+	for _, v := range w.vars {
+		switch v.Name {
+		case "SessionTime":
+			t, ok := decodeVar(v, frame).(float64)
+			if !ok {
+				continue
+			}
+			if w.SubHeaders.RecordCount == 0 {
+				w.SubHeaders.StartTime = t
+			}
+			w.SubHeaders.EndTime = t
+		case "Lap":
+			lap, ok := decodeVar(v, frame).(int)
+			if !ok {
+				continue
+			}
+			w.laps[lap] = struct{}{}
+		}
+	}
+	w.SubHeaders.LapCount = int32(len(w.laps))
+	w.SubHeaders.RecordCount++
+
+	return nil
+}
+
+// Close writes the final headers and disk sub headers. It doesn't close the
+// destination, that belongs to the caller
+func (w *IBTWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	var headerRaw [FileHeaderSize]byte
+	buf := bytes.NewBuffer(headerRaw[:0])
+	err := binary.Write(buf, binary.LittleEndian, &w.Headers)
+	if err != nil {
+		return fmt.Errorf("unable to pack headers: %v", err)
+	}
+	_, err = w.dst.WriteAt(headerRaw[:], 0)
+	if err != nil {
+		return fmt.Errorf("failed to write headers: %v", err)
+	}
+
+	var subheaderRaw [SubHeaderSize]byte
+	buf = bytes.NewBuffer(subheaderRaw[:0])
+	err = binary.Write(buf, binary.LittleEndian, &w.SubHeaders)
+	if err != nil {
+		return fmt.Errorf("unable to pack disk subheaders: %v", err)
+	}
+	_, err = w.dst.WriteAt(subheaderRaw[:], FileHeaderSize)
+	if err != nil {
+		return fmt.Errorf("failed to write disk subheaders: %v", err)
+	}
+
+	return nil
+}
+
+// encodeVarHeader packs a Var into its VarHeaderSize bytes on disk form
+func encodeVarHeader(v Var) ([]byte, error) {
+	dst := IBTVar{
+		Type:        v.Type,
+		Offset:      v.Offset,
+		Count:       v.Count,
+		CountAsTime: v.CountAsTime,
+	}
+	copy(dst.Name[:len(dst.Name)-1], v.Name)
+	copy(dst.Description[:len(dst.Description)-1], v.Description)
+	copy(dst.Unit[:len(dst.Unit)-1], v.Unit)
+
+	buf := bytes.NewBuffer(make([]byte, 0, VarHeaderSize))
+	err := binary.Write(buf, binary.LittleEndian, &dst)
+	if err != nil {
+		return nil, fmt.Errorf("unable to pack variable %s: %v", v.Name, err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,192 @@
+package goirsdk
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testSessionInfo = `---
+WeekendInfo:
+ TrackName: okayama full
+ TrackID: 166
+ SubSessionID: 12345
+ LeagueID: 77
+CameraInfo:
+ Groups:
+ - GroupNum: 1
+   GroupName: Nose
+   Cameras:
+   - CameraNum: 1
+     CameraName: CamNose
+ - GroupNum: 10
+   GroupName: TV1
+   Cameras:
+   - CameraNum: 1
+     CameraName: CamTV1_00
+   - CameraNum: 2
+     CameraName: CamTV1_01
+ - GroupNum: 21
+   GroupName: Blimp
+   IsScenic: true
+   Cameras:
+   - CameraNum: 1
+     CameraName: CamBlimp
+RadioInfo:
+ SelectedRadioNum: 0
+ Radios:
+ - RadioNum: 0
+   Frequencies:
+   - FrequencyNum: 0
+     FrequencyName: "@ALLTEAMS"
+     ClubID: 0
+   - FrequencyNum: 1
+     FrequencyName: "@Test Driver"
+     ClubID: 12
+DriverInfo:
+ DriverCarIdx: 0
+ DriverUserID: 4242
+ DriverSetupName: my secret setup.sto
+ Drivers:
+ - CarIdx: 0
+   UserName: Test Driver
+   UserID: 4242
+   TeamName: Test Team
+   CarNumber: "12"
+ - CarIdx: 1
+   UserName: Other Driver
+   UserID: 5151
+   TeamName: Other Team
+   CarNumber: "7"
+...
+`
+
+// testVars is the variable layout of the synthetic telemetry files
+var testVars = []Var{
+	{Type: IRSDK_double, Offset: 0, Count: 1, Name: "SessionTime", Unit: "s"},
+	{Type: IRSDK_int, Offset: 8, Count: 1, Name: "SessionNum"},
+	{Type: IRSDK_int, Offset: 12, Count: 1, Name: "Lap"},
+	{Type: IRSDK_float, Offset: 16, Count: 1, Name: "Speed", Unit: "m/s"},
+	{Type: IRSDK_bitField, Offset: 20, Count: 1, Name: "EngineWarnings", Unit: "irsdk_EngineWarnings"},
+	{Type: IRSDK_int, Offset: 24, Count: 1, Name: "Gear"},
+	{Type: IRSDK_bool, Offset: 28, Count: 1, Name: "IsOnTrack"},
+	{Type: IRSDK_int, Offset: 32, Count: 4, Name: "CarIdxLap"},
+}
+
+const testBufLen = 48
+
+// testFrame builds the data frame of a given tick for the synthetic files:
+// 60 ticks per lap and the session changes at tick 120
+func testFrame(tick int) []byte {
+	frame := make([]byte, testBufLen)
+	binary.LittleEndian.PutUint64(frame[0:], math.Float64bits(100+float64(tick)/60))
+	if tick >= 120 {
+		binary.LittleEndian.PutUint32(frame[8:], 1)
+	}
+	binary.LittleEndian.PutUint32(frame[12:], uint32(tick/60))
+	binary.LittleEndian.PutUint32(frame[16:], math.Float32bits(float32(tick)))
+	binary.LittleEndian.PutUint32(frame[20:], 0x10)
+	gear := int32(-1)
+	binary.LittleEndian.PutUint32(frame[24:], uint32(gear))
+	frame[28] = 1
+	for car := 0; car < 4; car++ {
+		binary.LittleEndian.PutUint32(frame[32+car*4:], uint32(tick/60+car))
+	}
+	return frame
+}
+
+// writeTestIBT writes a synthetic telemetry file with the given number of
+// frames and returns its path
+func writeTestIBT(t *testing.T, frames int) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.ibt")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer file.Close()
+
+	headers := TelemetryHeaders{Version: 2, Status: 1, TickRate: 60, BufLen: testBufLen}
+	w, err := NewIBTWriter(file, headers, DiskSubHeader{StartDate: 1729371732}, testVars, []byte(testSessionInfo))
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	for tick := 0; tick < frames; tick++ {
+		err = w.WriteFrame(testFrame(tick))
+		if err != nil {
+			t.Fatalf("Failed to write frame: %v", err)
+		}
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("Failed to close writer: %v", err)
+	}
+
+	return path
+}
+
+// openTestIBT opens a telemetry file with Init, closing it with the test
+func openTestIBT(t *testing.T, path string) *IBT {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open test file: %v", err)
+	}
+	t.Cleanup(func() { file.Close() })
+
+	ibt, err := Init(file, "", "")
+	if err != nil {
+		t.Fatalf("Failed to init from test file: %v", err)
+	}
+	return ibt
+}
+
+// TestIBTWriter_RoundTrip
+// A file written by the IBTWriter can be read back by Init and Update
+func TestIBTWriter_RoundTrip(t *testing.T) {
+	// Arrange
+	path := writeTestIBT(t, 150)
+
+	// Act
+	ibt := openTestIBT(t, path)
+	_, err := ibt.Update(0)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Error updating: %v", err)
+	}
+	expectedSubHeader := DiskSubHeader{
+		StartDate:   1729371732,
+		StartTime:   100,
+		EndTime:     100 + 149.0/60,
+		LapCount:    3,
+		RecordCount: 150,
+	}
+	if !cmp.Equal(&expectedSubHeader, ibt.SubHeaders) {
+		t.Fatalf("Expected:\n%#v\nGot:\n%#v\n", expectedSubHeader, ibt.SubHeaders)
+	}
+	if ibt.SessionInfo.WeekendInfo.TrackID != 166 {
+		t.Fatalf("Expected TrackID 166, got %d", ibt.SessionInfo.WeekendInfo.TrackID)
+	}
+	if gear := ibt.Vars.Vars["Gear"].Value; gear != -1 {
+		t.Fatalf("Expected Gear -1, got %v", gear)
+	}
+	if laps := ibt.Vars.Vars["CarIdxLap"].Value; !cmp.Equal(laps, []int32{0, 1, 2, 3}) {
+		t.Fatalf("Expected CarIdxLap [0 1 2 3], got %v", laps)
+	}
+	if !ibt.Vars.Vars["irsdk_pitSpeedLimiter"].Value.(bool) {
+		t.Fatalf("Expected the pit speed limiter to be active")
+	}
+
+	state, _ := ibt.Update(time.Millisecond)
+	if state != Running {
+		t.Fatalf("Expected Running, got %v", state)
+	}
+}
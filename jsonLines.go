@@ -0,0 +1,193 @@
+package goirsdk
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+)
+
+// JSONLinesOptions configures the JSON Lines (NDJSON) encoding of frames
+type JSONLinesOptions struct {
+	Vars      []string // Vars to encode, in this order. Empty encodes every variable
+	Precision int      // Precision is the digits after the decimal point, 0 keeps the shortest form
+	Bitfields bool     // Bitfields adds a <Name>_flags array with the set flags' names
+}
+
+// JSONLinesEncoder writes one JSON object per telemetry frame, with the tick
+// followed by the selected variables. Arrays are encoded as JSON arrays
+type JSONLinesEncoder struct {
+	w    io.Writer
+	opts JSONLinesOptions
+	vars []Var
+	keys [][]byte
+	line []byte
+	last []byte // last is the frame written by the last call to Encode
+}
+
+// ErrNoFrame means Update read no new frame to encode
+var ErrNoFrame = errors.New("no new frame was read by Update")
+
+// NewJSONLinesEncoder prepares an encoder for the variables of i
+func NewJSONLinesEncoder(w io.Writer, i *IBT, opts JSONLinesOptions) (*JSONLinesEncoder, error) {
+	vars, err := i.SelectVars(opts.Vars)
+	if err != nil {
+		return nil, err
+	}
+
+	e := JSONLinesEncoder{w: w, opts: opts}
+	for _, v := range vars {
+		key, err := json.Marshal(v.Name)
+		if err != nil {
+			return nil, err
+		}
+		e.vars = append(e.vars, v)
+		e.keys = append(e.keys, key)
+	}
+
+	return &e, nil
+}
+
+// Encode writes the frame read by the last call to Update, which makes it work
+// for both telemetry files and live data. Call it after Update returned
+// Running, ErrNoFrame is returned before the first frame and when Update read
+// no new frame since the last call
+func (e *JSONLinesEncoder) Encode(i *IBT) error {
+	if len(i.frame) == 0 {
+		return ErrNoFrame
+	}
+	// Every frame read by Update is a new buffer
+	if len(e.last) > 0 && &e.last[0] == &i.frame[0] {
+		return ErrNoFrame
+	}
+	e.last = i.frame
+
+	tick := i.Vars.Tick
+	if !i.source.Live() {
+		// Update already moved a telemetry file to the next tick
+		tick--
+	}
+	return e.EncodeFrame(tick, i.frame)
+}
+
+// EncodeFrame writes the data frame of a given tick as a single line
+func (e *JSONLinesEncoder) EncodeFrame(tick int32, frame []byte) error {
+	line := append(e.line[:0], `{"tick":`...)
+	line = strconv.AppendInt(line, int64(tick), 10)
+
+	for k, v := range e.vars {
+		line = append(line, ',')
+		line = append(line, e.keys[k]...)
+		line = append(line, ':')
+
+		if v.Type == IRSDK_char && v.Count > 1 {
+			// Char arrays are strings, not worth a JSON array of characters
+			line = appendJSONString(line, string(trimNull(frame[v.Offset:v.Offset+v.Count])))
+			continue
+		}
+
+		if v.Count > 1 {
+			line = append(line, '[')
+		}
+		for entry := int32(0); entry < v.Count; entry++ {
+			if entry > 0 {
+				line = append(line, ',')
+			}
+			line = e.appendValue(line, v, frame, entry)
+		}
+		if v.Count > 1 {
+			line = append(line, ']')
+		}
+
+		if e.opts.Bitfields && v.Type == IRSDK_bitField {
+			if table, ok := bitfieldTables[v.Name]; ok && v.Count == 1 {
+				line = append(line, ',')
+				line = append(line, e.keys[k][:len(e.keys[k])-1]...)
+				line = append(line, `_flags":[`...)
+				bits := int(binary.LittleEndian.Uint32(frame[v.Offset:]))
+				first := true
+				for _, flag := range table {
+					if bits&flag.Value == 0 {
+						continue
+					}
+					if !first {
+						line = append(line, ',')
+					}
+					line = appendJSONString(line, flag.Name)
+					first = false
+				}
+				line = append(line, ']')
+			}
+		}
+	}
+	line = append(line, '}', '\n')
+	e.line = line
+
+	_, err := e.w.Write(line)
+	return err
+}
+
+// appendValue encodes a single entry of a variable
+func (e *JSONLinesEncoder) appendValue(line []byte, v Var, frame []byte, entry int32) []byte {
+	value := entryNumber(v, frame, entry)
+
+	switch v.Type {
+	case IRSDK_bool:
+		return strconv.AppendBool(line, value != 0)
+	case IRSDK_char:
+		return appendJSONString(line, string(trimNull([]byte{byte(value)})))
+	case IRSDK_int, IRSDK_bitField:
+		return strconv.AppendInt(line, int64(value), 10)
+	}
+
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return append(line, "null"...)
+	}
+
+	bitSize := 64
+	if v.Type == IRSDK_float {
+		bitSize = 32
+	}
+	if e.opts.Precision > 0 {
+		return strconv.AppendFloat(line, value, 'f', e.opts.Precision, bitSize)
+	}
+	return strconv.AppendFloat(line, value, 'g', -1, bitSize)
+}
+
+// appendJSONString appends s as a quoted JSON string
+func appendJSONString(line []byte, s string) []byte {
+	quoted, _ := json.Marshal(s)
+	return append(line, quoted...)
+}
+
+// trimNull cuts a fixed size string at its first null byte
+func trimNull(raw []byte) []byte {
+	for k, b := range raw {
+		if b == 0 {
+			return raw[:k]
+		}
+	}
+	return raw
+}
+
+// ExportJSONLines streams every frame of a telemetry file into w as JSON Lines
+func (i *IBT) ExportJSONLines(w io.Writer, opts JSONLinesOptions) error {
+	e, err := NewJSONLinesEncoder(w, i, opts)
+	if err != nil {
+		return err
+	}
+
+	return i.eachFrame(func(tick int32, frame []byte) error {
+		return e.EncodeFrame(tick, frame)
+	})
+}
+
+// ExportSessionInfoJSON writes the session info as indented JSON, the JSON
+// counterpart of the YAML export
+func (i *IBT) ExportSessionInfoJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(i.SessionInfo)
+}
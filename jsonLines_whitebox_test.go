@@ -0,0 +1,104 @@
+package goirsdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestExportJSONLines_SelectedVars
+// Each frame becomes a JSON object with the selected variables, rounded floats
+// and the decoded bitfield flags
+func TestExportJSONLines_SelectedVars(t *testing.T) {
+	// Arrange
+	src := openTestIBT(t, writeTestIBT(t, 2))
+	var out bytes.Buffer
+
+	// Act
+	err := src.ExportJSONLines(&out, JSONLinesOptions{
+		Vars:      []string{"SessionTime", "Gear", "IsOnTrack", "EngineWarnings", "CarIdxLap"},
+		Precision: 2,
+		Bitfields: true,
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Error exporting: %v", err)
+	}
+	expected := `{"tick":0,"SessionTime":100.00,"Gear":-1,"IsOnTrack":true,"EngineWarnings":16,` +
+		`"EngineWarnings_flags":["irsdk_pitSpeedLimiter"],"CarIdxLap":[0,1,2,3]}` + "\n" +
+		`{"tick":1,"SessionTime":100.02,"Gear":-1,"IsOnTrack":true,"EngineWarnings":16,` +
+		`"EngineWarnings_flags":["irsdk_pitSpeedLimiter"],"CarIdxLap":[0,1,2,3]}` + "\n"
+	if !cmp.Equal(expected, out.String()) {
+		t.Fatalf("Expected:\n%s\nGot:\n%s\n", expected, out.String())
+	}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if !json.Valid([]byte(line)) {
+			t.Fatalf("Invalid JSON line: %s", line)
+		}
+	}
+}
+
+// TestJSONLinesEncoder_Encode
+// Encode writes the frame read by the last Update
+func TestJSONLinesEncoder_Encode(t *testing.T) {
+	// Arrange
+	src := openTestIBT(t, writeTestIBT(t, 10))
+	var out bytes.Buffer
+	e, err := NewJSONLinesEncoder(&out, src, JSONLinesOptions{Vars: []string{"Speed"}})
+	if err != nil {
+		t.Fatalf("Error creating encoder: %v", err)
+	}
+
+	// Act
+	for tick := 0; tick < 4; tick++ {
+		_, err = src.Update(0)
+		if err != nil {
+			t.Fatalf("Error updating: %v", err)
+		}
+	}
+	err = e.Encode(src)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Error encoding: %v", err)
+	}
+	if expected := "{\"tick\":3,\"Speed\":3}\n"; out.String() != expected {
+		t.Fatalf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+// TestJSONLinesEncoder_NoFrame
+// Encode refuses to run before the first frame and to write a frame again
+// once the file ended
+func TestJSONLinesEncoder_NoFrame(t *testing.T) {
+	// Arrange
+	src := openTestIBT(t, writeTestIBT(t, 1))
+	var out bytes.Buffer
+	e, err := NewJSONLinesEncoder(&out, src, JSONLinesOptions{Vars: []string{"Speed"}})
+	if err != nil {
+		t.Fatalf("Error creating encoder: %v", err)
+	}
+
+	// Act
+	errBefore := e.Encode(src)
+	src.Update(0)
+	errRunning := e.Encode(src)
+	state, _ := src.Update(0)
+	errEnded := e.Encode(src)
+
+	// Assert
+	if !errors.Is(errBefore, ErrNoFrame) || !errors.Is(errEnded, ErrNoFrame) {
+		t.Fatalf("Expected ErrNoFrame, got %v and %v", errBefore, errEnded)
+	}
+	if errRunning != nil || state != Ended {
+		t.Fatalf("Expected a frame then the end, got %v (%v)", state, errRunning)
+	}
+	if strings.Count(out.String(), "\n") != 1 {
+		t.Fatalf("Expected a single line, got %q", out.String())
+	}
+}
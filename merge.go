@@ -0,0 +1,80 @@
+package goirsdk
+
+import (
+	"fmt"
+	"io"
+)
+
+// Merge joins telemetry files of the same subsession into dst, like the ones
+// iRacing writes when recording is restarted. The sources must be given in
+// recording order.
+// - The variables are the union of the sources' variables, frames that lack a
+// variable have it zero filled
+// - The session info is taken from the last source, it is the most up to date
+// - Frames that go back in SessionNum/SessionTime, from overlapping
+// recordings, are dropped so the session time stays continuous
+func Merge(dst io.WriterAt, sources ...*IBT) error {
+	if len(sources) == 0 {
+		return fmt.Errorf("nothing to merge")
+	}
+
+	subSessionID := sources[0].SessionInfo.WeekendInfo.SubSessionID
+	seen := make(map[string]Var)
+	var union []Var
+	for _, src := range sources {
+		if src.source.Live() {
+			return fmt.Errorf("merging is only supported for telemetry files")
+		}
+		if id := src.SessionInfo.WeekendInfo.SubSessionID; id != subSessionID {
+			return fmt.Errorf("can't merge subsessions %d and %d", subSessionID, id)
+		}
+
+		for _, v := range src.SortedVars() {
+			prev, ok := seen[v.Name]
+			if !ok {
+				seen[v.Name] = v
+				union = append(union, v)
+				continue
+			}
+			if prev.Type != v.Type || prev.Count != v.Count {
+				return fmt.Errorf("variable %s has different types or counts across files", v.Name)
+			}
+		}
+	}
+	vars, bufLen := packVars(union)
+
+	last := sources[len(sources)-1]
+	sessionInfo, err := last.rawSessionInfo()
+	if err != nil {
+		return err
+	}
+
+	headers := *last.Headers
+	headers.BufLen = bufLen
+	w, err := NewIBTWriter(dst, headers, *sources[0].SubHeaders, vars, sessionInfo)
+	if err != nil {
+		return err
+	}
+
+	written := false
+	var lastNum, lastTime float64
+	for _, src := range sources {
+		err = src.eachFrame(func(_ int32, frame []byte) error {
+			num, _ := src.frameNumber(frame, "SessionNum")
+			t, ok := src.frameNumber(frame, "SessionTime")
+			if ok && written && (num < lastNum || (num == lastNum && t <= lastTime)) {
+				return nil
+			}
+			if ok {
+				lastNum, lastTime, written = num, t, true
+			}
+
+			return w.WriteFrame(src.remapFrame(frame, vars, bufLen))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.Close()
+}
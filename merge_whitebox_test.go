@@ -0,0 +1,90 @@
+package goirsdk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// rewriteTestIBT writes a new telemetry file with fn and returns its path
+func rewriteTestIBT(t *testing.T, fn func(dst *os.File) error) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "rewritten.ibt")
+	dst, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create output: %v", err)
+	}
+	defer dst.Close()
+
+	err = fn(dst)
+	if err != nil {
+		t.Fatalf("Failed to rewrite test file: %v", err)
+	}
+	return path
+}
+
+// TestMerge_OverlappingFilesWithDifferentVars
+// Merging a restarted recording drops the overlap and zero fills the
+// variables missing in one of the files
+func TestMerge_OverlappingFilesWithDifferentVars(t *testing.T) {
+	// Arrange
+	full := openTestIBT(t, writeTestIBT(t, 180))
+	first := openTestIBT(t, rewriteTestIBT(t, func(dst *os.File) error {
+		return full.Trim(dst, SelectTicks(0, 100))
+	}))
+	noGear := openTestIBT(t, rewriteTestIBT(t, func(dst *os.File) error {
+		return full.Subset(dst, []string{"SessionTime", "SessionNum", "Lap", "Speed", "EngineWarnings"})
+	}))
+	second := openTestIBT(t, rewriteTestIBT(t, func(dst *os.File) error {
+		return noGear.Trim(dst, SelectTicks(90, 180))
+	}))
+
+	// Act
+	path := rewriteTestIBT(t, func(dst *os.File) error {
+		return Merge(dst, first, second)
+	})
+
+	// Assert
+	merged := openTestIBT(t, path)
+	if merged.SubHeaders.RecordCount != 180 || merged.SubHeaders.LapCount != 3 {
+		t.Fatalf("Unexpected sub headers:\n%s", merged.SubHeaders.ToString())
+	}
+	if merged.Headers.NumVars != int32(len(testVars)) {
+		t.Fatalf("Expected %d variables, got %d", len(testVars), merged.Headers.NumVars)
+	}
+
+	for tick := 0; tick < 180; tick++ {
+		_, err := merged.Update(0)
+		if err != nil {
+			t.Fatalf("Error reading merged data: %v", err)
+		}
+
+		speed := merged.Vars.Vars["Speed"].Value.(float32)
+		if speed != float32(tick) {
+			t.Fatalf("Expected Speed %d at tick %d, got %f", tick, tick, speed)
+		}
+
+		expectedGear := -1
+		if tick >= 100 {
+			expectedGear = 0
+		}
+		if gear := merged.Vars.Vars["Gear"].Value.(int); gear != expectedGear {
+			t.Fatalf("Expected Gear %d at tick %d, got %d", expectedGear, tick, gear)
+		}
+	}
+}
+
+// TestMerge_DifferentSubSessions
+// Files of different subsessions are refused
+func TestMerge_DifferentSubSessions(t *testing.T) {
+	first := openTestIBT(t, writeTestIBT(t, 1))
+	second := openTestIBT(t, writeTestIBT(t, 1))
+	second.SessionInfo.WeekendInfo.SubSessionID++
+
+	err := Merge(nil, first, second)
+
+	if err == nil {
+		t.Fatalf("Expected an error merging different subsessions")
+	}
+}
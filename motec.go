@@ -0,0 +1,414 @@
+package goirsdk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// The MoTeC .ld layout isn't documented, the structures below follow the
+// reverse engineered format used by the open source ld parsers. Unnamed fields
+// are unknown and written as zeros
+const (
+	ldHeaderSize  = 1762
+	ldEventSize   = 1154
+	ldVenueSize   = 1100
+	ldVehicleSize = 260
+	ldChannelSize = 124
+)
+
+type ldHeader struct {
+	Marker        uint32 // Always 0x40
+	_             [4]byte
+	ChannelMeta   uint32 // Offset of the first channel header
+	ChannelData   uint32 // Offset of the first channel data
+	_             [20]byte
+	EventPtr      uint32
+	_             [24]byte
+	Unknown       [3]uint16
+	DeviceSerial  uint32
+	DeviceType    [8]byte
+	DeviceVersion uint16
+	Unknown2      uint16
+	NumChannels   uint32
+	_             [4]byte
+	Date          [16]byte // dd/mm/yyyy
+	_             [16]byte
+	Time          [16]byte // hh:mm:ss
+	_             [16]byte
+	Driver        [64]byte
+	Vehicle       [64]byte
+	_             [64]byte
+	Venue         [64]byte
+	_             [64]byte
+	_             [1024]byte
+	ProLogging    uint32
+	_             [66]byte
+	ShortComment  [64]byte
+	_             [126]byte
+}
+
+type ldEvent struct {
+	Name     [64]byte
+	Session  [64]byte
+	Comment  [1024]byte
+	VenuePtr uint16
+}
+
+type ldVenue struct {
+	Name       [64]byte
+	_          [1034]byte
+	VehiclePtr uint16
+}
+
+type ldVehicle struct {
+	ID      [64]byte
+	_       [128]byte
+	Weight  uint32
+	Type    [32]byte
+	Comment [32]byte
+}
+
+type ldChannel struct {
+	Prev      uint32 // Offset of the previous channel header, 0 for the first
+	Next      uint32 // Offset of the next channel header, 0 for the last
+	DataPtr   uint32
+	NumData   uint32
+	Counter   uint16
+	DataTypeA uint16 // 0x07 floats, 0x05 int32 and 0x03 int16
+	DataType  uint16 // Size of a sample in bytes
+	Freq      uint16 // Sample rate in Hz
+	Shift     int16  // value = (raw/Scale * 10^-Dec + Shift) * Mul
+	Mul       int16
+	Scale     int16
+	Dec       int16
+	Name      [32]byte
+	ShortName [8]byte
+	Unit      [12]byte
+	_         [40]byte
+}
+
+// motecChannel maps an iRacing variable to a MoTeC channel
+type motecChannel struct {
+	Name      string
+	ShortName string
+	Unit      string
+	Convert   func(float64) float64
+}
+
+// motecChannels holds the MoTeC names for the common iRacing variables, the
+// ones missing here keep their iRacing name
+var motecChannels = map[string]motecChannel{
+	"Speed":              {"Ground Speed", "Speed", "km/h", func(v float64) float64 { return v * 3.6 }},
+	"RPM":                {"Engine RPM", "RPM", "rpm", nil},
+	"Throttle":           {"Throttle Pos", "Throttle", "%", percent},
+	"Brake":              {"Brake Pos", "Brake", "%", percent},
+	"Clutch":             {"Clutch Pos", "Clutch", "%", percent},
+	"SteeringWheelAngle": {"Steering Angle", "Steer", "deg", func(v float64) float64 { return v * 180 / math.Pi }},
+	"Gear":               {"Gear", "Gear", "", nil},
+	"Lap":                {"Lap Number", "Lap", "", nil},
+	"LapDist":            {"Lap Distance", "LapDist", "m", nil},
+	"LapDistPct":         {"Lap Distance Pct", "LapPct", "%", percent},
+	"LatAccel":           {"G Force Lat", "GLat", "G", toG},
+	"LongAccel":          {"G Force Long", "GLong", "G", toG},
+	"VertAccel":          {"G Force Vert", "GVert", "G", toG},
+	"FuelLevel":          {"Fuel Level", "Fuel", "l", nil},
+	"Lat":                {"GPS Latitude", "GPSLat", "deg", nil},
+	"Lon":                {"GPS Longitude", "GPSLon", "deg", nil},
+	"Alt":                {"GPS Altitude", "GPSAlt", "m", nil},
+	"WaterTemp":          {"Engine Temp", "ETemp", "C", nil},
+	"OilTemp":            {"Oil Temp", "OTemp", "C", nil},
+	"OilPress":           {"Oil Pressure", "OPres", "kPa", nil},
+}
+
+// percent converts iRacing's 0..1 fractions into MoTeC's 0..100
+func percent(v float64) float64 {
+	return v * 100
+}
+
+// toG converts m/s^2 into G
+func toG(v float64) float64 {
+	return v / 9.80665
+}
+
+// motecUnits maps the iRacing units MoTeC spells differently
+var motecUnits = map[string]string{
+	"revs/min": "rpm",
+	"m/s^2":    "m/s/s",
+}
+
+// MoTeCOptions configures the MoTeC export
+type MoTeCOptions struct {
+	Vars []string // Vars to export, every numeric variable by default
+}
+
+// ldSample describes how a variable entry is stored as a MoTeC channel
+type ldSample struct {
+	v       Var
+	entry   int32
+	channel motecChannel
+	isFloat bool
+	dataPtr int64
+}
+
+// ExportMoTeC converts a telemetry file into a MoTeC i2 .ld file, written into
+// ld, and its .ldx companion with the lap beacons, written into ldx. Channels
+// are sampled at TickRate and the event metadata comes from the session info
+// and DiskSubHeader.StartDate
+func (i *IBT) ExportMoTeC(ld io.WriterAt, ldx io.Writer, opts MoTeCOptions) error {
+	vars, err := i.SelectVars(opts.Vars)
+	if err != nil {
+		return err
+	}
+
+	// A first pass gets the number of samples and the lap beacons
+	var frames int32
+	var beacons []float64
+	lastLap, hasLap := 0.0, false
+	err = i.eachFrame(func(tick int32, frame []byte) error {
+		frames++
+		lap, ok := i.frameNumber(frame, "Lap")
+		if ok && hasLap && lap > lastLap {
+			beacons = append(beacons, float64(tick)/float64(i.Headers.TickRate))
+		}
+		lastLap, hasLap = lap, ok
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var samples []ldSample
+	for _, v := range vars {
+		if v.Type == IRSDK_char {
+			continue
+		}
+		for entry, name := range columnNames(v) {
+			channel, ok := motecChannels[v.Name]
+			if !ok || v.Count > 1 {
+				channel = motecChannel{Name: name, ShortName: name, Unit: v.Unit}
+				if unit, ok := motecUnits[v.Unit]; ok {
+					channel.Unit = unit
+				}
+			}
+			samples = append(samples, ldSample{
+				v:       v,
+				entry:   int32(entry),
+				channel: channel,
+				isFloat: v.Type == IRSDK_float || v.Type == IRSDK_double || channel.Convert != nil,
+			})
+		}
+	}
+	if len(samples) == 0 {
+		return fmt.Errorf("no numeric variables to export")
+	}
+
+	eventPtr := int64(ldHeaderSize)
+	venuePtr := eventPtr + ldEventSize
+	vehiclePtr := venuePtr + ldVenueSize
+	metaPtr := vehiclePtr + ldVehicleSize
+	dataPtr := metaPtr + int64(len(samples))*ldChannelSize
+
+	err = i.writeLDMetadata(ld, len(samples), metaPtr, dataPtr, eventPtr, venuePtr, vehiclePtr)
+	if err != nil {
+		return err
+	}
+
+	for k := range samples {
+		samples[k].dataPtr = dataPtr
+		dataPtr += int64(frames) * 4
+
+		channel := ldChannel{
+			DataPtr: uint32(samples[k].dataPtr),
+			NumData: uint32(frames),
+			Counter: uint16(0x2ee1 + k),
+			Freq:    uint16(i.Headers.TickRate),
+			Mul:     1,
+			Scale:   1,
+		}
+		if k > 0 {
+			channel.Prev = uint32(metaPtr + int64(k-1)*ldChannelSize)
+		}
+		if k < len(samples)-1 {
+			channel.Next = uint32(metaPtr + int64(k+1)*ldChannelSize)
+		}
+		channel.DataTypeA, channel.DataType = 0x05, 4
+		if samples[k].isFloat {
+			channel.DataTypeA = 0x07
+		}
+		copy(channel.Name[:len(channel.Name)-1], samples[k].channel.Name)
+		copy(channel.ShortName[:len(channel.ShortName)-1], samples[k].channel.ShortName)
+		copy(channel.Unit[:len(channel.Unit)-1], samples[k].channel.Unit)
+
+		err = writeStruct(ld, metaPtr+int64(k)*ldChannelSize, &channel)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The second pass writes the samples straight into each channel's data
+	raw := make([]byte, 4)
+	err = i.eachFrame(func(tick int32, frame []byte) error {
+		for _, s := range samples {
+			value := sampleValue(s, frame)
+			if s.isFloat {
+				binary.LittleEndian.PutUint32(raw, math.Float32bits(float32(value)))
+			} else {
+				binary.LittleEndian.PutUint32(raw, uint32(int32(value)))
+			}
+
+			_, err := ld.WriteAt(raw, s.dataPtr+int64(tick)*4)
+			if err != nil {
+				return fmt.Errorf("failed to write channel data: %v", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if ldx == nil {
+		return nil
+	}
+	return writeLDX(ldx, beacons, float64(frames)/float64(i.Headers.TickRate))
+}
+
+// writeLDMetadata writes the .ld header and the event, venue and vehicle blocks
+func (i *IBT) writeLDMetadata(ld io.WriterAt, numChannels int,
+	metaPtr, dataPtr, eventPtr, venuePtr, vehiclePtr int64) error {
+	weekend := i.SessionInfo.WeekendInfo
+	driver, car := "", ""
+	for _, d := range i.SessionInfo.DriverInfo.Drivers {
+		if d.CarIdx == i.SessionInfo.DriverInfo.DriverCarIdx {
+			driver, car = d.UserName, d.CarScreenName
+		}
+	}
+	venue := weekend.TrackDisplayName
+	if weekend.TrackConfigName != "" {
+		venue = fmt.Sprintf("%s %s", venue, weekend.TrackConfigName)
+	}
+	session := ""
+	if sessions := i.SessionInfo.SessionInfo.Sessions; len(sessions) > 0 {
+		session = sessions[len(sessions)-1].SessionType
+	}
+	start := time.Unix(i.SubHeaders.StartDate, 0).UTC()
+
+	header := ldHeader{
+		Marker:        0x40,
+		ChannelMeta:   uint32(metaPtr),
+		ChannelData:   uint32(dataPtr),
+		EventPtr:      uint32(eventPtr),
+		Unknown:       [3]uint16{1, 0x4240, 0xf},
+		DeviceSerial:  0x1f44,
+		DeviceVersion: 420,
+		Unknown2:      0xadb0,
+		NumChannels:   uint32(numChannels),
+		ProLogging:    0xc81a4,
+	}
+	copy(header.DeviceType[:], "ADL")
+	copy(header.Date[:], start.Format("02/01/2006"))
+	copy(header.Time[:], start.Format("15:04:05"))
+	copy(header.Driver[:len(header.Driver)-1], driver)
+	copy(header.Vehicle[:len(header.Vehicle)-1], car)
+	copy(header.Venue[:len(header.Venue)-1], venue)
+	copy(header.ShortComment[:len(header.ShortComment)-1],
+		fmt.Sprintf("iRacing subsession %d", weekend.SubSessionID))
+
+	event := ldEvent{VenuePtr: uint16(venuePtr)}
+	copy(event.Name[:len(event.Name)-1], weekend.EventType)
+	copy(event.Session[:len(event.Session)-1], session)
+	copy(event.Comment[:len(event.Comment)-1],
+		fmt.Sprintf("SeriesID %d, SeasonID %d, SessionID %d, SubSessionID %d",
+			weekend.SeriesID, weekend.SeasonID, weekend.SessionID, weekend.SubSessionID))
+
+	v := ldVenue{VehiclePtr: uint16(vehiclePtr)}
+	copy(v.Name[:len(v.Name)-1], venue)
+
+	vehicle := ldVehicle{}
+	copy(vehicle.ID[:len(vehicle.ID)-1], car)
+
+	err := writeStruct(ld, 0, &header)
+	if err != nil {
+		return err
+	}
+	err = writeStruct(ld, eventPtr, &event)
+	if err != nil {
+		return err
+	}
+	err = writeStruct(ld, venuePtr, &v)
+	if err != nil {
+		return err
+	}
+	return writeStruct(ld, vehiclePtr, &vehicle)
+}
+
+// sampleValue reads the value of a MoTeC channel out of a data frame
+func sampleValue(s ldSample, frame []byte) float64 {
+	value := entryNumber(s.v, frame, s.entry)
+	if s.channel.Convert != nil {
+		value = s.channel.Convert(value)
+	}
+	return value
+}
+
+// writeStruct packs a little endian struct at a given offset
+func writeStruct(w io.WriterAt, offset int64, data interface{}) error {
+	buf := bytes.NewBuffer(make([]byte, 0, binary.Size(data)))
+	err := binary.Write(buf, binary.LittleEndian, data)
+	if err != nil {
+		return fmt.Errorf("unable to pack data: %v", err)
+	}
+
+	_, err = w.WriteAt(buf.Bytes(), offset)
+	if err != nil {
+		return fmt.Errorf("failed to write data: %v", err)
+	}
+	return nil
+}
+
+// writeLDX writes the .ldx XML with a beacon per lap crossing, in seconds since
+// the start of the file, and the lap details MoTeC shows in its summary
+func writeLDX(w io.Writer, beacons []float64, duration float64) error {
+	var sb strings.Builder
+	sb.WriteString("<?xml version=\"1.0\"?>\n")
+	sb.WriteString("<LDXFile Locale=\"English_United States.1252\" DefaultLocale=\"C\" Version=\"1.6\">\n")
+	sb.WriteString(" <Layers>\n  <Layer>\n   <MarkerBlock>\n")
+	sb.WriteString("    <MarkerGroup Name=\"Beacons\" Index=\"3\">\n")
+	for k, t := range beacons {
+		fmt.Fprintf(&sb, "     <Marker Version=\"100\" ClassName=\"BCN\" Name=\"Manual.%d\" Flags=\"77\" Time=\"%.6fe6\"/>\n",
+			k+1, t)
+	}
+	sb.WriteString("    </MarkerGroup>\n   </MarkerBlock>\n   <RangeBlock/>\n  </Layer>\n")
+
+	// Only the laps between two beacons are complete, the first beacon ends the
+	// file's first lap
+	fastestLap, fastestTime := 0, 0.0
+	for k := 1; k < len(beacons); k++ {
+		lapTime := beacons[k] - beacons[k-1]
+		if fastestLap == 0 || lapTime < fastestTime {
+			fastestLap, fastestTime = k+1, lapTime
+		}
+	}
+	sb.WriteString("  <Details>\n")
+	fmt.Fprintf(&sb, "   <String Id=\"Total Laps\" Value=\"%d\"/>\n", len(beacons)+1)
+	fmt.Fprintf(&sb, "   <String Id=\"Duration\" Value=\"%s\"/>\n", formatLapTime(duration))
+	if fastestLap > 0 {
+		fmt.Fprintf(&sb, "   <String Id=\"Fastest Time\" Value=\"%s\"/>\n", formatLapTime(fastestTime))
+		fmt.Fprintf(&sb, "   <String Id=\"Fastest Lap\" Value=\"%d\"/>\n", fastestLap)
+	}
+	sb.WriteString("  </Details>\n </Layers>\n</LDXFile>\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// formatLapTime renders seconds as m:ss.sss
+func formatLapTime(t float64) string {
+	minutes := int(t / 60)
+	return fmt.Sprintf("%d:%06.3f", minutes, t-float64(minutes*60))
+}
@@ -0,0 +1,84 @@
+package goirsdk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMoTeC_StructSizes
+// The .ld blocks have the sizes MoTeC expects
+func TestMoTeC_StructSizes(t *testing.T) {
+	sizes := map[string][2]int{
+		"header":  {binary.Size(ldHeader{}), ldHeaderSize},
+		"event":   {binary.Size(ldEvent{}), ldEventSize},
+		"venue":   {binary.Size(ldVenue{}), ldVenueSize},
+		"vehicle": {binary.Size(ldVehicle{}), ldVehicleSize},
+		"channel": {binary.Size(ldChannel{}), ldChannelSize},
+	}
+
+	for name, size := range sizes {
+		if size[0] != size[1] {
+			t.Errorf("Expected %s to have %d bytes, got %d", name, size[1], size[0])
+		}
+	}
+}
+
+// TestExportMoTeC_ChannelsAndBeacons
+// Channels are renamed and converted, and each lap crossing gets a beacon
+func TestExportMoTeC_ChannelsAndBeacons(t *testing.T) {
+	// Arrange
+	src := openTestIBT(t, writeTestIBT(t, 150))
+	path := filepath.Join(t.TempDir(), "test.ld")
+	ld, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create output: %v", err)
+	}
+	defer ld.Close()
+	var ldx bytes.Buffer
+
+	// Act
+	err = src.ExportMoTeC(ld, &ldx, MoTeCOptions{Vars: []string{"Speed", "Gear"}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Error exporting: %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+
+	var header ldHeader
+	err = binary.Read(bytes.NewReader(raw), binary.LittleEndian, &header)
+	if err != nil {
+		t.Fatalf("Failed to parse header: %v", err)
+	}
+	if header.NumChannels != 2 || string(bytes.TrimRight(header.Date[:], "\x00")) != "19/10/2024" {
+		t.Fatalf("Unexpected header: %d channels on %s", header.NumChannels, header.Date)
+	}
+
+	var speed ldChannel
+	err = binary.Read(bytes.NewReader(raw[header.ChannelMeta:]), binary.LittleEndian, &speed)
+	if err != nil {
+		t.Fatalf("Failed to parse channel: %v", err)
+	}
+	if name := string(bytes.TrimRight(speed.Name[:], "\x00")); name != "Ground Speed" || speed.NumData != 150 {
+		t.Fatalf("Unexpected channel %s with %d samples", name, speed.NumData)
+	}
+	sample := math.Float32frombits(binary.LittleEndian.Uint32(raw[speed.DataPtr+10*4:]))
+	if sample != 36 {
+		t.Fatalf("Expected 36 km/h at tick 10, got %f", sample)
+	}
+
+	if n := strings.Count(ldx.String(), "ClassName=\"BCN\""); n != 2 {
+		t.Fatalf("Expected 2 beacons, got %d in:\n%s", n, ldx.String())
+	}
+	if !strings.Contains(ldx.String(), "Time=\"1.000000e6\"") {
+		t.Fatalf("Expected a beacon at 1s in:\n%s", ldx.String())
+	}
+}
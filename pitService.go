@@ -0,0 +1,281 @@
+package goirsdk
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	litersPerGallon = 3.785411784 // US gallon
+	kPaPerPSI       = 6.894757293
+)
+
+// FuelUnit is the unit of a fuel amount
+type FuelUnit int
+
+const (
+	Liters  FuelUnit = iota
+	Gallons          // US gallons
+)
+
+// PressureUnit is the unit of a tire pressure
+type PressureUnit int
+
+const (
+	KPa PressureUnit = iota
+	PSI
+)
+
+// Tire is a corner of the car
+type Tire int
+
+const (
+	LeftFront Tire = iota
+	RightFront
+	LeftRear
+	RightRear
+)
+
+// tireCommands are the PitCommand modes changing each tire
+var tireCommands = [...]int{PitCommandLF, PitCommandRF, PitCommandLR, PitCommandRR}
+
+// tireFlags are the PitSvFlags of each tire change
+var tireFlags = [...]int{PitSvLFTireChange, PitSvRFTireChange, PitSvLRTireChange, PitSvRRTireChange}
+
+// tireChange is the service of a single tire
+type tireChange struct {
+	change   bool
+	pressure float64 // kPa, 0 keeps the current pressure
+}
+
+// PitPlan is the pit service to request, built by chaining its methods:
+//
+//	plan := NewPitPlan().Fuel(5, Gallons).AllTires(24, PSI).Tearoff()
+//
+// The clear operations are sent before the changes, so a plan can start from
+// a clean state with ClearAll and then pick the service it wants
+type PitPlan struct {
+	clearAll   bool
+	clearTires bool
+	clearFuel  bool
+	clearWS    bool
+	clearFR    bool
+	fuel       float64 // liters, 0 keeps the current amount
+	fuelSet    bool
+	tires      [4]tireChange
+	tearoff    bool
+	fastRepair bool
+	err        error // First invalid value given to the builder
+}
+
+// NewPitPlan creates an empty plan, applying it changes nothing
+func NewPitPlan() *PitPlan {
+	return &PitPlan{}
+}
+
+// Fuel adds amount of fuel, 0 adds the amount already set in the pit service
+func (p *PitPlan) Fuel(amount float64, unit FuelUnit) *PitPlan {
+	if amount < 0 || math.IsNaN(amount) {
+		p.fail(fmt.Errorf("invalid fuel amount %v", amount))
+		return p
+	}
+	if unit == Gallons {
+		amount *= litersPerGallon
+	}
+	p.fuel = amount
+	p.fuelSet = true
+	return p
+}
+
+// Tire changes a tire, setting its pressure. A pressure of 0 keeps the one
+// already set in the pit service
+func (p *PitPlan) Tire(t Tire, pressure float64, unit PressureUnit) *PitPlan {
+	if t < LeftFront || t > RightRear {
+		p.fail(fmt.Errorf("invalid tire %d", t))
+		return p
+	}
+	if pressure < 0 || math.IsNaN(pressure) {
+		p.fail(fmt.Errorf("invalid tire pressure %v", pressure))
+		return p
+	}
+	if unit == PSI {
+		pressure *= kPaPerPSI
+	}
+	p.tires[t] = tireChange{change: true, pressure: pressure}
+	return p
+}
+
+// AllTires changes the four tires with the same pressure
+func (p *PitPlan) AllTires(pressure float64, unit PressureUnit) *PitPlan {
+	for t := LeftFront; t <= RightRear; t++ {
+		p.Tire(t, pressure, unit)
+	}
+	return p
+}
+
+// Tearoff uses a windshield tearoff
+func (p *PitPlan) Tearoff() *PitPlan {
+	p.tearoff = true
+	return p
+}
+
+// FastRepair uses a fast repair
+func (p *PitPlan) FastRepair() *PitPlan {
+	p.fastRepair = true
+	return p
+}
+
+// ClearAll unchecks every pit service
+func (p *PitPlan) ClearAll() *PitPlan {
+	p.clearAll = true
+	return p
+}
+
+// ClearTires unchecks the tire changes
+func (p *PitPlan) ClearTires() *PitPlan {
+	p.clearTires = true
+	return p
+}
+
+// ClearFuel unchecks the refueling
+func (p *PitPlan) ClearFuel() *PitPlan {
+	p.clearFuel = true
+	return p
+}
+
+// ClearTearoff unchecks the windshield tearoff
+func (p *PitPlan) ClearTearoff() *PitPlan {
+	p.clearWS = true
+	return p
+}
+
+// ClearFastRepair unchecks the fast repair
+func (p *PitPlan) ClearFastRepair() *PitPlan {
+	p.clearFR = true
+	return p
+}
+
+// fail keeps the first error of the builder
+func (p *PitPlan) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+// Messages returns the broadcast messages applying the plan. maxFuel is the
+// capacity of the tank in liters, 0 when unknown, and flags the PitSvFlags of
+// the service currently set: clears of services that aren't set are skipped
+// and so are the windshield and fast repair requests already set
+func (p *PitPlan) Messages(maxFuel float64, flags int) ([]Msg, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.fuelSet && p.clearFuel {
+		return nil, fmt.Errorf("the plan both adds and clears the fuel")
+	}
+	if p.tearoff && p.clearWS {
+		return nil, fmt.Errorf("the plan both uses and clears the tearoff")
+	}
+	if p.fastRepair && p.clearFR {
+		return nil, fmt.Errorf("the plan both uses and clears the fast repair")
+	}
+	for _, tire := range p.tires {
+		if tire.pressure > math.MaxUint16 {
+			return nil, fmt.Errorf("tire pressure of %.0f kPa is too high", tire.pressure)
+		}
+	}
+	if p.fuelSet && p.fuel > math.MaxUint16 {
+		return nil, fmt.Errorf("%.0f liters of fuel is too much", p.fuel)
+	}
+	if p.fuelSet && maxFuel > 0 && p.fuel > maxFuel {
+		return nil, fmt.Errorf("%.1f liters of fuel is over the %.1f liters of the tank", p.fuel, maxFuel)
+	}
+
+	var msgs []Msg
+	pit := func(mode int, parameter float64) {
+		// The parameter is split in the low and high words of P2 and P3
+		value := uint32(math.Round(parameter))
+		msgs = append(msgs, Msg{
+			Cmd: BroadcastPitCommand,
+			P1:  int32(mode),
+			P2:  int32(value & 0xFFFF),
+			P3:  int32(value >> 16),
+		})
+	}
+
+	switch {
+	case p.clearAll:
+		if flags != 0 {
+			pit(PitCommandClear, 0)
+		}
+		flags = 0
+	default:
+		if p.clearTires && flags&(tireFlags[0]|tireFlags[1]|tireFlags[2]|tireFlags[3]) != 0 {
+			pit(PitCommandClearTires, 0)
+		}
+		if p.clearFuel && flags&PitSvFuelFill != 0 {
+			pit(PitCommandClearFuel, 0)
+		}
+		if p.clearWS && flags&PitSvWindshieldTearoff != 0 {
+			pit(PitCommandClearWS, 0)
+		}
+		if p.clearFR && flags&PitSvFastRepair != 0 {
+			pit(PitCommandClearFR, 0)
+		}
+	}
+
+	if p.fuelSet {
+		pit(PitCommandFuel, p.fuel)
+	}
+	for t, tire := range p.tires {
+		if tire.change {
+			pit(tireCommands[t], tire.pressure)
+		}
+	}
+	if p.tearoff && flags&PitSvWindshieldTearoff == 0 {
+		pit(PitCommandWS, 0)
+	}
+	if p.fastRepair && flags&PitSvFastRepair == 0 {
+		pit(PitCommandFR, 0)
+	}
+
+	return msgs, nil
+}
+
+// ApplyPitPlan sends the messages of a pit plan, checking the fuel against
+// the tank of the car and the clears against the current PitSvFlags
+func (i *IBT) ApplyPitPlan(p *PitPlan) error {
+	maxFuel := 0.0
+	if i.SessionInfo != nil {
+		maxFuel = i.SessionInfo.DriverInfo.DriverCarFuelMaxLtr
+		if pct := i.SessionInfo.DriverInfo.DriverCarMaxFuelPct; pct > 0 {
+			maxFuel *= pct
+		}
+	}
+
+	msgs, err := p.Messages(maxFuel, i.pitServiceFlags())
+	if err != nil {
+		return err
+	}
+
+	for _, m := range msgs {
+		err = i.broadcast(m.Cmd, int(m.P1), int(m.P2), int(m.P3))
+		if err != nil {
+			return fmt.Errorf("failed to send pit command %d: %v", m.P1, err)
+		}
+	}
+	return nil
+}
+
+// pitServiceFlags returns the PitSvFlags of the last frame, 0 before the first
+// Update
+func (i *IBT) pitServiceFlags() int {
+	if i.Vars == nil {
+		return 0
+	}
+	v, ok := i.Vars.Vars["PitSvFlags"]
+	if !ok || i.frame == nil || int(v.Offset)+4 > len(i.frame) {
+		return 0
+	}
+	return int(entryNumber(v, i.frame, 0))
+}
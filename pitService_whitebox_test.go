@@ -0,0 +1,104 @@
+package goirsdk
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// pitMsg builds the expected message of a pit command
+func pitMsg(mode int, parameter int32) Msg {
+	return Msg{Cmd: BroadcastPitCommand, P1: int32(mode), P2: parameter & 0xFFFF, P3: parameter >> 16}
+}
+
+// TestPitPlan_Messages
+// Units are converted and the clears and requests already satisfied by the
+// current PitSvFlags are skipped
+func TestPitPlan_Messages(t *testing.T) {
+	// Arrange
+	plan := NewPitPlan().
+		ClearTires().
+		ClearFastRepair().
+		Fuel(10, Gallons).
+		Tire(LeftFront, 180, KPa).
+		Tire(RightRear, 25, PSI).
+		Tearoff()
+	// Tires and tearoff set, no fast repair
+	flags := PitSvLFTireChange | PitSvWindshieldTearoff
+
+	// Act
+	msgs, err := plan.Messages(100, flags)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to build messages: %v", err)
+	}
+	expected := []Msg{
+		pitMsg(PitCommandClearTires, 0),
+		pitMsg(PitCommandFuel, 38),
+		pitMsg(PitCommandLF, 180),
+		pitMsg(PitCommandRR, 172),
+	}
+	if diff := cmp.Diff(expected, msgs); diff != "" {
+		t.Errorf("Messages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestPitPlan_Invalid
+// Plans over the tank capacity, contradicting themselves or with invalid
+// values are refused
+func TestPitPlan_Invalid(t *testing.T) {
+	tests := map[string]*PitPlan{
+		"over capacity":      NewPitPlan().Fuel(120, Liters),
+		"fuel and clear":     NewPitPlan().Fuel(10, Liters).ClearFuel(),
+		"repair and clear":   NewPitPlan().FastRepair().ClearFastRepair(),
+		"negative pressure":  NewPitPlan().AllTires(-1, KPa),
+		"unknown tire":       NewPitPlan().Tire(Tire(7), 150, KPa),
+		"negative fuel":      NewPitPlan().Fuel(-5, Gallons),
+		"tearoff and clear":  NewPitPlan().Tearoff().ClearTearoff(),
+		"pressure too large": NewPitPlan().Tire(LeftRear, 70000, KPa),
+	}
+
+	for name, plan := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			_, err := plan.Messages(100, 0)
+
+			// Assert
+			if err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
+
+// TestApplyPitPlan
+// The messages of the plan are broadcast in order
+func TestApplyPitPlan(t *testing.T) {
+	// Arrange
+	ibt := openTestIBT(t, writeTestIBT(t, 1))
+	rec := &RecordingTransport{}
+	ibt.SetBroadcastTransport(rec)
+
+	// Act
+	err := ibt.ApplyPitPlan(NewPitPlan().Fuel(70000, Liters))
+	if err == nil {
+		t.Fatalf("Expected an error for too much fuel")
+	}
+	err = ibt.ApplyPitPlan(NewPitPlan().ClearAll().AllTires(0, KPa).FastRepair())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to apply pit plan: %v", err)
+	}
+	expected := []Msg{
+		pitMsg(PitCommandLF, 0),
+		pitMsg(PitCommandRF, 0),
+		pitMsg(PitCommandLR, 0),
+		pitMsg(PitCommandRR, 0),
+		pitMsg(PitCommandFR, 0),
+	}
+	if diff := cmp.Diff(expected, rec.Messages()); diff != "" {
+		t.Errorf("Messages mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,842 @@
+package goirsdk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ESilva15/goirsdk/wire"
+)
+
+const (
+	relayMagic = "IBTR"
+
+	relayMaxMessage       = 64 << 20        // Largest message accepted, a session info is far smaller
+	relayQueueSize        = 256             // Messages waiting to be sent to a client or read by Update
+	relayHandshakeTimeout = 5 * time.Second // Time a client has to say hello
+	relayCloseTimeout     = 5 * time.Second // Time the clients have to take their queued messages on Close
+)
+
+// Relay message types, every message is its type, the length of its payload
+// and the payload
+const (
+	relayImage       byte = 1 // Headers, var headers and session info laid out like the memory map
+	relayFrame       byte = 2 // Tick and data frame
+	relaySessionInfo byte = 3 // SessionInfoUpdate and the raw session info
+	relayStatus      byte = 4 // Status of the headers
+	relayEnd         byte = 5 // The source has no more frames
+	relayHello       byte = 6 // The versions and variables a client wants, see wire.Hello
+	relayWelcome     byte = 7 // The version and variables picked by the server, see wire.Welcome
+)
+
+// ErrRelayClosed means the relay server went away
+var ErrRelayClosed = errors.New("the relay connection was closed")
+
+// relayMsg is a message of the relay protocol
+type relayMsg struct {
+	typ     byte
+	payload []byte
+}
+
+// writeRelayMsg writes a message to w
+func writeRelayMsg(w io.Writer, m relayMsg) error {
+	var head [5]byte
+	head[0] = m.typ
+	binary.LittleEndian.PutUint32(head[1:], uint32(len(m.payload)))
+	_, err := w.Write(head[:])
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(m.payload)
+	return err
+}
+
+// readRelayMsg reads a message from r
+func readRelayMsg(r io.Reader) (relayMsg, error) {
+	var head [5]byte
+	_, err := io.ReadFull(r, head[:])
+	if err != nil {
+		return relayMsg{}, err
+	}
+	size := binary.LittleEndian.Uint32(head[1:])
+	if size > relayMaxMessage {
+		return relayMsg{}, fmt.Errorf("relay message of %d bytes is too large", size)
+	}
+
+	payload := make([]byte, size)
+	_, err = io.ReadFull(r, payload)
+	if err != nil {
+		return relayMsg{}, err
+	}
+	return relayMsg{typ: head[0], payload: payload}, nil
+}
+
+// int32Payload packs a value and the bytes following it
+func int32Payload(value int32, data []byte) []byte {
+	payload := make([]byte, 4+len(data))
+	binary.LittleEndian.PutUint32(payload, uint32(value))
+	copy(payload[4:], data)
+	return payload
+}
+
+// relayImageOf lays out the headers, var headers and session info of an IBT
+// the way the memory map does. The data buffers aren't part of it
+func relayImageOf(i *IBT) ([]byte, error) {
+	sessionInfo, err := i.rawSessionInfo()
+	if err != nil {
+		return nil, err
+	}
+	vars := i.SortedVars()
+
+	headers := TelemetryHeaders{
+		Version:           i.Headers.Version,
+		Status:            i.Headers.Status,
+		TickRate:          i.Headers.TickRate,
+		SessionInfoUpdate: i.Headers.SessionInfoUpdate,
+		SessionInfoLength: int32(len(sessionInfo)),
+		NumVars:           int32(len(vars)),
+		VarHeaderOffset:   FileHeaderSize + SubHeaderSize,
+		NumBuf:            1,
+		BufLen:            i.Headers.BufLen,
+	}
+	headers.SessionInfoOffset = headers.VarHeaderOffset + headers.NumVars*VarHeaderSize
+	headers.BufOffset = headers.SessionInfoOffset + headers.SessionInfoLength
+
+	buf := bytes.NewBuffer(make([]byte, 0, headers.BufOffset))
+	err = binary.Write(buf, binary.LittleEndian, &headers)
+	if err != nil {
+		return nil, fmt.Errorf("unable to pack headers: %v", err)
+	}
+	buf.Write(make([]byte, FileHeaderSize-buf.Len()))
+
+	// Files have a disk sub header, live sessions leave it empty
+	var sub [SubHeaderSize]byte
+	if !i.source.Live() {
+		_, err = i.source.ReadAt(sub[:], FileHeaderSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read disk sub header: %v", err)
+		}
+	}
+	buf.Write(sub[:])
+
+	for _, v := range vars {
+		raw, err := encodeVarHeader(v)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(raw)
+	}
+	buf.Write(sessionInfo)
+
+	return buf.Bytes(), nil
+}
+
+// RelayServer streams the data an IBT reads to the RelayClients connected to
+// it over TCP: the headers, variables and session info when they connect,
+// then every frame Update reads and the session info and status changes
+type RelayServer struct {
+	// KeyframeInterval is the number of frames between the keyframes of the
+	// delta encoding, wire.DefaultKeyframeInterval when 0
+	KeyframeInterval int
+	// CloseTimeout is the time Close leaves the clients to take their queued
+	// messages before they are disconnected, relayCloseTimeout when 0
+	CloseTimeout time.Duration
+
+	ibt     *IBT
+	mu      sync.Mutex
+	image   []byte // image sent to the clients when they connect
+	peers   map[*relayPeer]struct{}
+	status  int32
+	closed  bool
+	ln      net.Listener
+	stopped chan struct{}
+}
+
+// relayPeer is a client of a RelayServer
+type relayPeer struct {
+	conn     net.Conn
+	channels []wire.Channel // Variables the client subscribed to
+	enc      *wire.Encoder
+	queue    chan relayMsg
+	done     chan struct{}
+	stop     sync.Once
+}
+
+// close tells the writer of the client to stop
+func (p *relayPeer) close() {
+	p.stop.Do(func() { close(p.done) })
+}
+
+// NewRelayServer creates a server relaying the data of ibt. The server reads
+// nothing by itself, call Run or Publish after every Update
+func NewRelayServer(ibt *IBT) (*RelayServer, error) {
+	image, err := relayImageOf(ibt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RelayServer{
+		ibt:     ibt,
+		image:   image,
+		peers:   map[*relayPeer]struct{}{},
+		status:  ibt.Headers.Status,
+		stopped: make(chan struct{}),
+	}, nil
+}
+
+// Serve accepts clients on l until Close is called
+func (s *RelayServer) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrRelayClosed
+	}
+	s.ln = l
+	s.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			select {
+			case <-s.stopped:
+				return nil
+			default:
+				return fmt.Errorf("failed to accept relay client: %v", err)
+			}
+		}
+		go s.add(conn)
+	}
+}
+
+// add negotiates the version and variables with a new client, then sends it
+// the current image and streams to it
+func (s *RelayServer) add(conn net.Conn) {
+	conn.SetDeadline(time.Now().Add(relayHandshakeTimeout))
+	r := bufio.NewReader(conn)
+	magic := make([]byte, len(relayMagic))
+	_, err := io.ReadFull(r, magic)
+	if err != nil || string(magic) != relayMagic {
+		conn.Close()
+		return
+	}
+	m, err := readRelayMsg(r)
+	if err != nil || m.typ != relayHello {
+		conn.Close()
+		return
+	}
+	var hello wire.Hello
+	err = hello.UnmarshalBinary(m.payload)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	conn.SetDeadline(time.Time{})
+
+	p := &relayPeer{conn: conn, queue: make(chan relayMsg, relayQueueSize), done: make(chan struct{})}
+	var welcome wire.Welcome
+	version, err := wire.Negotiate(hello.Versions, wire.Versions)
+	if err == nil {
+		s.mu.Lock()
+		var channels []wire.Channel
+		channels, err = imageChannels(s.image)
+		s.mu.Unlock()
+		p.channels = wire.Subscribe(channels, hello.Channels)
+		for _, c := range p.channels {
+			welcome.Channels = append(welcome.Channels, c.Name)
+		}
+	}
+	if err == nil {
+		p.enc, err = wire.NewEncoder(version, p.channels, s.KeyframeInterval)
+	}
+	if err == nil {
+		welcome.Version = version
+	}
+	payload, _ := welcome.MarshalBinary()
+	p.queue <- relayMsg{typ: relayWelcome, payload: payload}
+	if err != nil {
+		// The welcome without a version tells the client why it's dropped
+		p.queue <- relayMsg{typ: relayEnd}
+		conn.SetWriteDeadline(time.Now().Add(relayHandshakeTimeout))
+		go s.write(p)
+		return
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		conn.Close()
+		return
+	}
+	// The image is queued under the lock, so the client gets every frame
+	// published after it
+	p.queue <- relayMsg{typ: relayImage, payload: filterImageVars(s.image, welcome.Channels)}
+	s.peers[p] = struct{}{}
+	s.mu.Unlock()
+
+	go s.write(p)
+}
+
+// write sends the queued messages of a client until it goes away
+func (s *RelayServer) write(p *relayPeer) {
+	defer s.remove(p)
+
+	w := bufio.NewWriter(p.conn)
+	_, err := w.WriteString(relayMagic)
+	if err != nil {
+		return
+	}
+
+	for {
+		var m relayMsg
+		select {
+		case m = <-p.queue:
+		case <-p.done:
+			// Closing, what is already queued is still sent
+			select {
+			case m = <-p.queue:
+			default:
+				w.Flush()
+				return
+			}
+		}
+
+		if m.typ == relayFrame {
+			// Frames are encoded as they are sent, the encoder follows what
+			// this client received when live frames are dropped
+			tick := int32(binary.LittleEndian.Uint32(m.payload))
+			m.payload, err = p.enc.Encode(nil, tick, m.payload[4:])
+			if err != nil {
+				return
+			}
+		}
+
+		err = writeRelayMsg(w, m)
+		if err == nil && len(p.queue) == 0 {
+			err = w.Flush()
+		}
+		if err != nil || m.typ == relayEnd {
+			w.Flush()
+			return
+		}
+	}
+}
+
+// remove drops a client
+func (s *RelayServer) remove(p *relayPeer) {
+	s.mu.Lock()
+	delete(s.peers, p)
+	s.mu.Unlock()
+	p.close()
+	p.conn.Close()
+}
+
+// send queues a message for every client. Live frames are dropped for the
+// clients falling behind, the rest waits for room in their queue
+func (s *RelayServer) send(m relayMsg) {
+	s.mu.Lock()
+	peers := make([]*relayPeer, 0, len(s.peers))
+	for p := range s.peers {
+		peers = append(peers, p)
+	}
+	s.mu.Unlock()
+
+	droppable := m.typ == relayFrame && s.ibt.source.Live()
+	for _, p := range peers {
+		if droppable {
+			select {
+			case p.queue <- m:
+			default:
+			}
+			continue
+		}
+		select {
+		case p.queue <- m:
+		case <-p.done:
+		}
+	}
+}
+
+// Publish sends the frame the last Update read, and the session info or
+// status when they changed since the last call
+func (s *RelayServer) Publish() error {
+	changed, err := s.ibt.RefreshSessionInfo()
+	if err != nil {
+		return err
+	}
+	if changed {
+		raw, err := s.ibt.rawSessionInfo()
+		if err != nil {
+			return err
+		}
+		s.PublishSessionInfo(s.ibt.Headers.SessionInfoUpdate, raw)
+	}
+
+	if s.ibt.source.Live() {
+		err = s.ibt.refreshStatus()
+		if err != nil {
+			return err
+		}
+	}
+	if status := s.ibt.Headers.Status; status != s.status {
+		s.setStatus(status)
+	}
+
+	if s.ibt.frame == nil {
+		return nil
+	}
+	s.send(relayMsg{typ: relayFrame, payload: int32Payload(s.ibt.Vars.Tick, s.ibt.frame)})
+	return nil
+}
+
+// PublishSessionInfo sends a new session info, already encoded like the one
+// of the telemetry data, to the clients
+func (s *RelayServer) PublishSessionInfo(update int32, raw []byte) {
+	s.mu.Lock()
+	s.image = replaceImageSessionInfo(s.image, update, raw)
+	s.mu.Unlock()
+	s.send(relayMsg{typ: relaySessionInfo, payload: int32Payload(update, raw)})
+}
+
+// setStatus sends a new Status to the clients
+func (s *RelayServer) setStatus(status int32) {
+	s.mu.Lock()
+	s.status = status
+	image := make([]byte, len(s.image))
+	copy(image, s.image)
+	binary.LittleEndian.PutUint32(image[statusOffset:], uint32(status))
+	s.image = image
+	s.mu.Unlock()
+	s.send(relayMsg{typ: relayStatus, payload: int32Payload(status, nil)})
+}
+
+// Run calls Update and Publish until ctx is done or the data ends, which the
+// clients are told about
+func (s *RelayServer) Run(ctx context.Context, timeout time.Duration) error {
+	for ctx.Err() == nil {
+		state, err := s.ibt.Update(timeout)
+		if err != nil && !errors.Is(err, ErrTornRead) {
+			return fmt.Errorf("failed to update: %v", err)
+		}
+		if state == Ended {
+			s.send(relayMsg{typ: relayEnd})
+			return nil
+		}
+		if state != Running {
+			continue
+		}
+
+		err = s.Publish()
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// Close stops accepting clients and disconnects them once their queued
+// messages are sent, or once CloseTimeout expires
+func (s *RelayServer) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.stopped)
+	peers := s.peers
+	s.peers = map[*relayPeer]struct{}{}
+	ln := s.ln
+	s.mu.Unlock()
+
+	// A client that stopped reading would keep its writer blocked forever
+	timeout := s.CloseTimeout
+	if timeout <= 0 {
+		timeout = relayCloseTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	for p := range peers {
+		p.conn.SetWriteDeadline(deadline)
+		p.close()
+	}
+	if ln != nil {
+		return ln.Close()
+	}
+	return nil
+}
+
+// replaceImageSessionInfo returns a copy of an image with another session
+// info, which always is at its end
+func replaceImageSessionInfo(image []byte, update int32, raw []byte) []byte {
+	offset := binary.LittleEndian.Uint32(image[20:])
+	updated := make([]byte, int(offset)+len(raw))
+	copy(updated, image[:offset])
+	copy(updated[offset:], raw)
+
+	binary.LittleEndian.PutUint32(updated[sessionInfoUpdateOffset:], uint32(update))
+	binary.LittleEndian.PutUint32(updated[sessionInfoUpdateOffset+4:], uint32(len(raw)))
+	binary.LittleEndian.PutUint32(updated[52:], uint32(len(updated)))
+	return updated
+}
+
+// relayFrameData is a frame received by a RelayClient
+type relayFrameData struct {
+	tick  int32
+	frame []byte
+}
+
+// RelayClient is the TelemetrySource of the data a RelayServer streams, an
+// IBT initialized with it through InitSource is used like a live one
+type RelayClient struct {
+	conn    net.Conn
+	dec     *wire.Decoder
+	version uint8
+	mu      sync.Mutex
+	image   []byte
+	frames  chan relayFrameData
+	pending *relayFrameData // pending was received by WaitForFrame
+	err     error           // err is why the stream ended, io.EOF when the source did
+	done    chan struct{}
+}
+
+// DialRelay connects to a RelayServer and waits up to timeout for the
+// headers, variables and session info. With vars only those variables are
+// streamed, the others are left out of the variables of the IBT
+func DialRelay(addr string, timeout time.Duration, vars ...string) (*RelayClient, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to relay: %v", err)
+	}
+	c, r, err := handshakeRelay(conn, timeout, vars)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	go c.read(r)
+	return c, nil
+}
+
+// handshakeRelay says hello to the server and reads its welcome and image
+func handshakeRelay(conn net.Conn, timeout time.Duration, vars []string) (*RelayClient, io.Reader, error) {
+	conn.SetDeadline(time.Now().Add(timeout))
+	defer conn.SetDeadline(time.Time{})
+
+	hello, _ := wire.Hello{Versions: wire.Versions, Channels: vars}.MarshalBinary()
+	_, err := conn.Write([]byte(relayMagic))
+	if err == nil {
+		err = writeRelayMsg(conn, relayMsg{typ: relayHello, payload: hello})
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to send relay hello: %v", err)
+	}
+
+	r := bufio.NewReader(conn)
+	magic := make([]byte, len(relayMagic))
+	_, err = io.ReadFull(r, magic)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read relay handshake: %v", err)
+	}
+	if string(magic) != relayMagic {
+		return nil, nil, fmt.Errorf("%s is not a telemetry relay", conn.RemoteAddr())
+	}
+
+	m, err := readRelayMsg(r)
+	if err != nil || m.typ != relayWelcome {
+		return nil, nil, fmt.Errorf("the relay didn't welcome the client: %v", err)
+	}
+	var welcome wire.Welcome
+	err = welcome.UnmarshalBinary(m.payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	if welcome.Version == 0 {
+		return nil, nil, wire.ErrNoCommonVersion
+	}
+
+	m, err = readRelayMsg(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read relay headers: %v", err)
+	}
+	if m.typ != relayImage {
+		return nil, nil, fmt.Errorf("the relay didn't start with the headers")
+	}
+	// The image comes from the network, nothing in it is trusted
+	err = checkRelayImage(m.payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	channels, err := imageChannels(m.payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid relay variables: %v", err)
+	}
+
+	bufLen := int(int32(binary.LittleEndian.Uint32(m.payload[36:])))
+	dec, err := wire.NewDecoder(channels, bufLen)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := &RelayClient{
+		conn:    conn,
+		image:   m.payload,
+		dec:     dec,
+		version: welcome.Version,
+		frames:  make(chan relayFrameData, relayQueueSize),
+		done:    make(chan struct{}),
+	}
+	return c, r, nil
+}
+
+// Version returns the protocol version picked with the server
+func (c *RelayClient) Version() uint8 {
+	return c.version
+}
+
+// read receives the messages of the server until the stream ends
+func (c *RelayClient) read(r io.Reader) {
+	defer close(c.frames)
+
+	for {
+		m, err := readRelayMsg(r)
+		if err != nil {
+			c.end(ErrRelayClosed)
+			return
+		}
+
+		switch m.typ {
+		case relayFrame:
+			tick, frame, err := c.dec.Decode(m.payload)
+			if err != nil {
+				c.end(fmt.Errorf("invalid relay frame: %v", err))
+				return
+			}
+			f := relayFrameData{tick: tick, frame: frame}
+			select {
+			case c.frames <- f:
+			case <-c.done:
+				c.end(ErrRelayClosed)
+				return
+			}
+		case relaySessionInfo:
+			if len(m.payload) < 4 {
+				c.end(fmt.Errorf("invalid relay session info"))
+				return
+			}
+			update := int32(binary.LittleEndian.Uint32(m.payload))
+			c.mu.Lock()
+			c.image = replaceImageSessionInfo(c.image, update, m.payload[4:])
+			c.mu.Unlock()
+		case relayStatus:
+			if len(m.payload) < 4 {
+				c.end(fmt.Errorf("invalid relay status"))
+				return
+			}
+			c.setStatus(m.payload[:4])
+		case relayEnd:
+			c.end(io.EOF)
+			return
+		}
+	}
+}
+
+// end records why the stream ended, a connection lost marks the session as
+// disconnected
+func (c *RelayClient) end(err error) {
+	c.mu.Lock()
+	if c.err == nil {
+		c.err = err
+	}
+	c.mu.Unlock()
+
+	if err != io.EOF {
+		c.setStatus([]byte{0, 0, 0, 0})
+	}
+}
+
+// setStatus writes the Status of the headers
+func (c *RelayClient) setStatus(raw []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	image := make([]byte, len(c.image))
+	copy(image, c.image)
+	copy(image[statusOffset:], raw)
+	c.image = image
+}
+
+// ReadAt reads the headers, variables and session info
+func (c *RelayClient) ReadAt(p []byte, off int64) (int, error) {
+	c.mu.Lock()
+	image := c.image
+	c.mu.Unlock()
+
+	if off >= int64(len(image)) {
+		return 0, io.EOF
+	}
+	n := copy(p, image[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Live is true, the frames are read as they arrive
+func (c *RelayClient) Live() bool {
+	return true
+}
+
+// WaitForFrame waits for the next frame of the server. The end of the stream
+// counts as a frame, for ReadFrame to report it
+func (c *RelayClient) WaitForFrame(timeout time.Duration) bool {
+	if c.pending != nil {
+		return true
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case f, ok := <-c.frames:
+		if ok {
+			c.pending = &f
+		}
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// ReadFrame returns the frames in the order the server sent them
+func (c *RelayClient) ReadFrame(headers *TelemetryHeaders, tick int32) (int32, []byte, error) {
+	f := c.pending
+	c.pending = nil
+	if f == nil {
+		next, ok := <-c.frames
+		if ok {
+			f = &next
+		}
+	}
+	if f == nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.err == nil {
+			return tick, nil, ErrRelayClosed
+		}
+		return tick, nil, c.err
+	}
+
+	if int32(len(f.frame)) != headers.BufLen {
+		return tick, nil, fmt.Errorf("relay frame of %d bytes, expected %d", len(f.frame), headers.BufLen)
+	}
+	return f.tick, f.frame, nil
+}
+
+// Close disconnects from the server
+func (c *RelayClient) Close() error {
+	select {
+	case <-c.done:
+		return nil
+	default:
+	}
+	close(c.done)
+	return c.conn.Close()
+}
+
+// checkRelayImage checks the headers of an image received from a server hold
+// within it, the variables are checked by imageChannels
+func checkRelayImage(image []byte) error {
+	if len(image) < FileHeaderSize+SubHeaderSize {
+		return fmt.Errorf("relay headers of %d bytes are too short", len(image))
+	}
+	field := func(offset int) int64 {
+		return int64(int32(binary.LittleEndian.Uint32(image[offset:])))
+	}
+	size := int64(len(image))
+
+	numVars, varOffset := field(24), field(28)
+	if numVars < 0 || varOffset < FileHeaderSize || varOffset+numVars*VarHeaderSize > size {
+		return fmt.Errorf("relay variable headers are out of the headers")
+	}
+	infoOffset, infoLength := field(20), field(16)
+	if infoOffset < varOffset+numVars*VarHeaderSize || infoLength < 0 || infoOffset+infoLength > size {
+		return fmt.Errorf("relay session info is out of the headers")
+	}
+	if bufLen := field(36); bufLen <= 0 || bufLen > relayMaxMessage {
+		return fmt.Errorf("invalid relay frame length %d", bufLen)
+	}
+	return nil
+}
+
+// imageChannels lists the variables of an image as wire channels, in the
+// order of their offsets. The headers of the image must have been checked
+func imageChannels(image []byte) ([]wire.Channel, error) {
+	numVars := int(int32(binary.LittleEndian.Uint32(image[24:])))
+	offset := int(int32(binary.LittleEndian.Uint32(image[28:])))
+	bufLen := int64(int32(binary.LittleEndian.Uint32(image[36:])))
+
+	var channels []wire.Channel
+	for k := 0; k < numVars; k++ {
+		raw := image[offset+k*VarHeaderSize : offset+(k+1)*VarHeaderSize]
+		v := parseVarHeader(raw)
+		vt, ok := VarTypes[int(v.Type)]
+		if !ok {
+			return nil, fmt.Errorf("variable %s has an unknown type %d", v.Name, v.Type)
+		}
+		size := int64(v.Count) * int64(vt.Size)
+		if v.Offset < 0 || v.Count < 0 || int64(v.Offset)+size > bufLen {
+			return nil, fmt.Errorf("variable %s is out of the %d bytes frame", v.Name, bufLen)
+		}
+		channels = append(channels, wire.Channel{Name: v.Name, Offset: int(v.Offset), Size: int(size)})
+	}
+	sort.Slice(channels, func(a, b int) bool { return channels[a].Offset < channels[b].Offset })
+	return channels, nil
+}
+
+// filterImageVars returns a copy of an image with only the named variables,
+// the frames keep their layout
+func filterImageVars(image []byte, names []string) []byte {
+	numVars := int(int32(binary.LittleEndian.Uint32(image[24:])))
+	varOffset := int(int32(binary.LittleEndian.Uint32(image[28:])))
+	infoOffset := int(int32(binary.LittleEndian.Uint32(image[20:])))
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(image)))
+	buf.Write(image[:varOffset])
+	kept := 0
+	for k := 0; k < numVars; k++ {
+		raw := image[varOffset+k*VarHeaderSize : varOffset+(k+1)*VarHeaderSize]
+		if slices.Contains(names, parseVarHeader(raw).Name) {
+			buf.Write(raw)
+			kept++
+		}
+	}
+	buf.Write(image[infoOffset:])
+
+	filtered := buf.Bytes()
+	sessionInfoOffset := varOffset + kept*VarHeaderSize
+	sessionInfoLength := len(image) - infoOffset
+	binary.LittleEndian.PutUint32(filtered[20:], uint32(sessionInfoOffset))
+	binary.LittleEndian.PutUint32(filtered[24:], uint32(kept))
+	binary.LittleEndian.PutUint32(filtered[52:], uint32(sessionInfoOffset+sessionInfoLength))
+	return filtered
+}
+
+// parseVarHeader reads the type, offset, count and name of a variable header
+func parseVarHeader(raw []byte) Var {
+	name := raw[16 : 16+32]
+	if end := bytes.IndexByte(name, 0); end >= 0 {
+		name = name[:end]
+	}
+	return Var{
+		Type:   int32(binary.LittleEndian.Uint32(raw[0:])),
+		Offset: int32(binary.LittleEndian.Uint32(raw[4:])),
+		Count:  int32(binary.LittleEndian.Uint32(raw[8:])),
+		Name:   string(name),
+	}
+}
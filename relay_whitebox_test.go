@@ -0,0 +1,374 @@
+package goirsdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ESilva15/goirsdk/wire"
+	"github.com/google/go-cmp/cmp"
+)
+
+// startTestRelay serves a telemetry file on a local port and connects a
+// client to it
+func startTestRelay(t *testing.T, frames int) (*RelayServer, *IBT) {
+	t.Helper()
+
+	server, err := NewRelayServer(openTestIBT(t, writeTestIBT(t, frames)))
+	if err != nil {
+		t.Fatalf("Failed to create relay server: %v", err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.Serve(l)
+	t.Cleanup(func() { server.Close() })
+
+	client, err := DialRelay(l.Addr().String(), time.Second)
+	if err != nil {
+		t.Fatalf("Failed to connect to relay: %v", err)
+	}
+	remote, err := InitSource(client, "", "")
+	if err != nil {
+		t.Fatalf("Failed to init from relay: %v", err)
+	}
+	t.Cleanup(remote.Close)
+
+	return server, remote
+}
+
+// TestRelay_Frames
+// A client reads every frame of a relayed file with the usual API
+func TestRelay_Frames(t *testing.T) {
+	// Arrange
+	server, remote := startTestRelay(t, 150)
+	done := make(chan error, 1)
+	go func() { done <- server.Run(context.Background(), time.Second) }()
+
+	// Act
+	var speeds []float32
+	var state IRacingState
+	var err error
+	for {
+		state, err = remote.Update(time.Second)
+		if state != Running {
+			break
+		}
+		speeds = append(speeds, remote.Vars.Vars["Speed"].Value.(float32))
+	}
+
+	// Assert
+	if state != Ended || err != nil {
+		t.Fatalf("Expected the relay to end, got %v (%v)", state, err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Relay server failed: %v", err)
+	}
+	if len(speeds) != 150 {
+		t.Fatalf("Expected 150 frames, got %d", len(speeds))
+	}
+	for k, speed := range speeds {
+		if speed != float32(k) {
+			t.Fatalf("Expected speed %d at frame %d, got %v", k, k, speed)
+		}
+	}
+	if remote.SessionInfo.WeekendInfo.TrackID != 166 || len(remote.SortedVars()) != len(testVars) {
+		t.Fatalf("Expected the session info and variables of the file")
+	}
+}
+
+// TestRelay_SessionInfoAndClose
+// New session info reaches the clients, which see the session disconnected
+// when the server goes away
+func TestRelay_SessionInfoAndClose(t *testing.T) {
+	// Arrange
+	server, remote := startTestRelay(t, 1)
+	updated := strings.Replace(testSessionInfo, "TrackID: 166", "TrackID: 167", 1)
+
+	// Act
+	server.PublishSessionInfo(2, []byte(updated))
+	changed := false
+	for deadline := time.Now().Add(time.Second); !changed && time.Now().Before(deadline); {
+		var err error
+		changed, err = remote.RefreshSessionInfo()
+		if err != nil {
+			t.Fatalf("Failed to refresh session info: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	server.Close()
+	state, err := remote.Update(time.Second)
+
+	// Assert
+	if !changed || remote.SessionInfo.WeekendInfo.TrackID != 167 {
+		t.Fatalf("Expected the updated session info, got track %d", remote.SessionInfo.WeekendInfo.TrackID)
+	}
+	if state != Failed || err == nil {
+		t.Fatalf("Expected the closed relay to fail the update, got %v (%v)", state, err)
+	}
+	if remote.IsConnected() {
+		t.Fatalf("Expected the session to be disconnected")
+	}
+}
+
+// TestRelay_WireRoundTrip
+// Every frame of a telemetry file decodes back to itself through the delta
+// encoding of the relay
+func TestRelay_WireRoundTrip(t *testing.T) {
+	// Arrange
+	ibt := openTestIBT(t, writeTestIBT(t, 200))
+
+	// Act
+	channels, encoded, raw := wireRoundTrip(t, ibt, 200)
+
+	// Assert
+	if channels != len(testVars) || encoded >= raw {
+		t.Fatalf("Expected %d channels smaller than the frames, got %d and %d/%d bytes",
+			len(testVars), channels, encoded, raw)
+	}
+}
+
+// TestRelay_WireRoundTripRecorded
+// The first minute of a recorded race, the sample TestFunctionality reads,
+// decodes back to itself through the delta encoding of the relay. No
+// recording is checked in, the test is skipped without it
+func TestRelay_WireRoundTripRecorded(t *testing.T) {
+	// Arrange
+	const path = "../testTelemetry/supercars_race_watkins_glenn.ibt"
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("No recorded telemetry: %v", err)
+	}
+	ibt := openTestIBT(t, path)
+
+	// Act
+	channels, encoded, raw := wireRoundTrip(t, ibt, 3600)
+
+	// Assert
+	if channels != len(ibt.SortedVars()) || encoded >= raw {
+		t.Fatalf("Expected %d channels smaller than the frames, got %d and %d/%d bytes",
+			len(ibt.SortedVars()), channels, encoded, raw)
+	}
+}
+
+// wireRoundTrip encodes and decodes up to frames frames of a telemetry file,
+// failing on the first one that doesn't come back the same. It returns the
+// number of channels and the encoded and raw sizes
+func wireRoundTrip(t *testing.T, ibt *IBT, frames int32) (int, int, int) {
+	t.Helper()
+
+	image, err := relayImageOf(ibt)
+	if err != nil {
+		t.Fatalf("Failed to lay out image: %v", err)
+	}
+	channels, err := imageChannels(image)
+	if err != nil {
+		t.Fatalf("Failed to list channels: %v", err)
+	}
+	enc, _ := wire.NewEncoder(wire.VersionDelta, channels, 10)
+	dec, err := wire.NewDecoder(channels, int(ibt.Headers.BufLen))
+	if err != nil {
+		t.Fatalf("Failed to create decoder: %v", err)
+	}
+
+	encoded, raw := 0, 0
+	for tick := int32(0); tick < frames; tick++ {
+		frame, err := ibt.ReadFrame(tick)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read frame %d: %v", tick, err)
+		}
+		data, err := enc.Encode(nil, tick, frame)
+		if err != nil {
+			t.Fatalf("Failed to encode frame %d: %v", tick, err)
+		}
+		gotTick, got, err := dec.Decode(data)
+		if err != nil || gotTick != tick || !bytes.Equal(got, frame) {
+			t.Fatalf("Frame %d didn't survive the encoding: tick %d, %v", tick, gotTick, err)
+		}
+		encoded += len(data)
+		raw += len(frame)
+	}
+	return len(channels), encoded, raw
+}
+
+// TestRelay_Subscribe
+// A client subscribed to some variables only sees those
+func TestRelay_Subscribe(t *testing.T) {
+	// Arrange
+	server, err := NewRelayServer(openTestIBT(t, writeTestIBT(t, 3)))
+	if err != nil {
+		t.Fatalf("Failed to create relay server: %v", err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.Serve(l)
+	defer server.Close()
+
+	// Act
+	client, err := DialRelay(l.Addr().String(), time.Second, "Speed", "Unknown")
+	if err != nil {
+		t.Fatalf("Failed to connect to relay: %v", err)
+	}
+	remote, err := InitSource(client, "", "")
+	if err != nil {
+		t.Fatalf("Failed to init from relay: %v", err)
+	}
+	defer remote.Close()
+	go server.Run(context.Background(), time.Second)
+	var speeds []float32
+	for {
+		state, _ := remote.Update(time.Second)
+		if state != Running {
+			break
+		}
+		speeds = append(speeds, remote.Vars.Vars["Speed"].Value.(float32))
+	}
+
+	// Assert
+	if client.Version() != wire.VersionDelta {
+		t.Fatalf("Expected the delta version, got %d", client.Version())
+	}
+	vars := remote.SortedVars()
+	if len(vars) != 1 || vars[0].Name != "Speed" {
+		t.Fatalf("Expected only Speed, got %v", vars)
+	}
+	if diff := cmp.Diff([]float32{0, 1, 2}, speeds); diff != "" {
+		t.Fatalf("Speeds mismatch (-want +got):\n%s", diff)
+	}
+	if remote.SessionInfo.WeekendInfo.TrackID != 166 {
+		t.Fatalf("Expected the session info of the file")
+	}
+}
+
+// TestDialRelay_MalformedImage
+// A server sending headers or variables out of its image is refused
+func TestDialRelay_MalformedImage(t *testing.T) {
+	image, err := relayImageOf(openTestIBT(t, writeTestIBT(t, 1)))
+	if err != nil {
+		t.Fatalf("Failed to lay out image: %v", err)
+	}
+	varOffset := int(binary.LittleEndian.Uint32(image[28:]))
+
+	tests := []struct {
+		name   string
+		image  []byte
+		offset int // offset of the int32 overwritten
+		value  int32
+	}{
+		{name: "Truncated", image: image[:FileHeaderSize]},
+		{name: "Too many vars", image: image, offset: 24, value: 1 << 20},
+		{name: "Negative var offset", image: image, offset: 28, value: -VarHeaderSize},
+		{name: "Session info past the end", image: image, offset: 20, value: int32(len(image))},
+		{name: "Negative frame length", image: image, offset: 36, value: -1},
+		{name: "Unknown var type", image: image, offset: varOffset, value: 42},
+		{name: "Var before the frame", image: image, offset: varOffset + 4, value: -8},
+		{name: "Var past the frame", image: image, offset: varOffset + 8, value: 1 << 20},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			corrupt := bytes.Clone(test.image)
+			if test.offset > 0 {
+				binary.LittleEndian.PutUint32(corrupt[test.offset:], uint32(test.value))
+			}
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("Failed to listen: %v", err)
+			}
+			defer l.Close()
+			go func() {
+				conn, err := l.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				welcome, _ := wire.Welcome{Version: wire.VersionDelta}.MarshalBinary()
+				conn.Write([]byte(relayMagic))
+				writeRelayMsg(conn, relayMsg{typ: relayWelcome, payload: welcome})
+				writeRelayMsg(conn, relayMsg{typ: relayImage, payload: corrupt})
+				io.Copy(io.Discard, conn)
+			}()
+
+			// Act
+			client, err := DialRelay(l.Addr().String(), time.Second)
+
+			// Assert
+			if err == nil {
+				client.Close()
+				t.Fatalf("Expected the malformed image to be refused")
+			}
+		})
+	}
+}
+
+// TestRelay_CloseStalledClient
+// Close disconnects a client that stopped reading once CloseTimeout expires
+func TestRelay_CloseStalledClient(t *testing.T) {
+	// Arrange
+	server, err := NewRelayServer(openTestIBT(t, writeTestIBT(t, 1)))
+	if err != nil {
+		t.Fatalf("Failed to create relay server: %v", err)
+	}
+	server.CloseTimeout = 20 * time.Millisecond
+	local, remote := net.Pipe()
+	defer local.Close()
+	go server.add(remote)
+	hello, _ := wire.Hello{Versions: wire.Versions}.MarshalBinary()
+	local.Write([]byte(relayMagic))
+	writeRelayMsg(local, relayMsg{typ: relayHello, payload: hello})
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		server.mu.Lock()
+		added := len(server.peers) == 1
+		server.mu.Unlock()
+		if added {
+			break
+		}
+	}
+
+	// Act
+	server.Close()
+	local.SetWriteDeadline(time.Now().Add(time.Second))
+	_, err = local.Write([]byte{0})
+
+	// Assert
+	if !errors.Is(err, io.ErrClosedPipe) {
+		t.Fatalf("Expected the stalled client to be disconnected, got %v", err)
+	}
+}
+
+// TestReplaceImageSessionInfo
+// The frames of an image with another session info start after it
+func TestReplaceImageSessionInfo(t *testing.T) {
+	// Arrange
+	image, err := relayImageOf(openTestIBT(t, writeTestIBT(t, 1)))
+	if err != nil {
+		t.Fatalf("Failed to lay out image: %v", err)
+	}
+	raw := []byte(testSessionInfo + "\nExtra: 1\n")
+
+	// Act
+	updated := replaceImageSessionInfo(image, 2, raw)
+
+	// Assert
+	offset := binary.LittleEndian.Uint32(updated[20:])
+	length := binary.LittleEndian.Uint32(updated[16:])
+	if got := binary.LittleEndian.Uint32(updated[52:]); got != offset+length || int(got) != len(updated) {
+		t.Errorf("Expected the frames at %d, got %d", offset+length, got)
+	}
+	if err := checkRelayImage(updated); err != nil {
+		t.Errorf("Expected a consistent image, got %v", err)
+	}
+}
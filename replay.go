@@ -0,0 +1,252 @@
+package goirsdk
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrReplayUnconfirmed means the telemetry didn't show the replay reaching
+// the requested state within the confirmation timeout
+var ErrReplayUnconfirmed = errors.New("the replay didn't reach the requested state in time")
+
+// ReplayOptions configures a ReplayController
+type ReplayOptions struct {
+	// ConfirmTimeout is how long a command waits for the telemetry to show it
+	// took effect, calling Update meanwhile. With 0 commands return once sent
+	ConfirmTimeout time.Duration
+	// TimeTolerance is how far from the requested session time a seek may
+	// land, half a second by default
+	TimeTolerance time.Duration
+}
+
+// ReplayController drives the replay of a live session with broadcast
+// messages and confirms every command with the Replay* variables
+type ReplayController struct {
+	ibt  *IBT
+	opts ReplayOptions
+}
+
+// NewReplayController creates a controller sending its commands through ibt
+func NewReplayController(ibt *IBT, opts ReplayOptions) *ReplayController {
+	if opts.TimeTolerance <= 0 {
+		opts.TimeTolerance = 500 * time.Millisecond
+	}
+	return &ReplayController{ibt: ibt, opts: opts}
+}
+
+// Play plays the replay at normal speed
+func (r *ReplayController) Play() error {
+	return r.SetSpeed(1)
+}
+
+// Pause stops the replay on the current frame
+func (r *ReplayController) Pause() error {
+	return r.SetSpeed(0)
+}
+
+// SetSpeed plays the replay at speed times the normal speed, negative speeds
+// rewind
+func (r *ReplayController) SetSpeed(speed int) error {
+	err := r.ibt.ReplaySetPlaySpeed(speed, false)
+	if err != nil {
+		return err
+	}
+	return r.confirm(func() bool {
+		return r.is("ReplayPlaySpeed", float64(speed)) && (speed == 0 || r.is("ReplayPlaySlowMotion", 0))
+	}, "ReplayPlaySpeed", "ReplayPlaySlowMotion")
+}
+
+// SlowMotion plays the replay at the normal speed divided by divider,
+// negative dividers rewind
+func (r *ReplayController) SlowMotion(divider int) error {
+	err := r.ibt.ReplaySetPlaySpeed(divider, true)
+	if err != nil {
+		return err
+	}
+	return r.confirm(func() bool {
+		return r.is("ReplayPlaySpeed", float64(divider)) && r.is("ReplayPlaySlowMotion", 1)
+	}, "ReplayPlaySpeed", "ReplayPlaySlowMotion")
+}
+
+// ToStart jumps to the start of the replay
+func (r *ReplayController) ToStart() error {
+	return r.search(RpySrchToStart)
+}
+
+// ToEnd jumps to the end of the replay, back to the live session
+func (r *ReplayController) ToEnd() error {
+	return r.search(RpySrchToEnd)
+}
+
+// NextLap jumps to the start of the next lap
+func (r *ReplayController) NextLap() error {
+	return r.search(RpySrchNextLap)
+}
+
+// PrevLap jumps to the start of the previous lap
+func (r *ReplayController) PrevLap() error {
+	return r.search(RpySrchPrevLap)
+}
+
+// NextIncident jumps to the next incident
+func (r *ReplayController) NextIncident() error {
+	return r.search(RpySrchNextIncident)
+}
+
+// PrevIncident jumps to the previous incident
+func (r *ReplayController) PrevIncident() error {
+	return r.search(RpySrchPrevIncident)
+}
+
+// NextSession jumps to the start of the next session
+func (r *ReplayController) NextSession() error {
+	return r.search(RpySrchNextSession)
+}
+
+// PrevSession jumps to the start of the previous session
+func (r *ReplayController) PrevSession() error {
+	return r.search(RpySrchPrevSession)
+}
+
+// NextFrame moves the replay a frame forward
+func (r *ReplayController) NextFrame() error {
+	return r.search(RpySrchNextFrame)
+}
+
+// PrevFrame moves the replay a frame back
+func (r *ReplayController) PrevFrame() error {
+	return r.search(RpySrchPrevFrame)
+}
+
+// search sends a RpySrch mode, confirmed once ReplayFrameNum changes. A search
+// leaving the replay where it was, like NextLap on the last lap, times out.
+// A playing replay is paused for the search, see paused
+func (r *ReplayController) search(mode int) error {
+	return r.paused(func() error {
+		before, _ := r.number("ReplayFrameNum")
+		err := r.ibt.ReplaySearch(mode)
+		if err != nil {
+			return err
+		}
+		return r.confirm(func() bool {
+			return !r.is("ReplayFrameNum", before)
+		}, "ReplayFrameNum")
+	})
+}
+
+// SeekFrame jumps to a frame counted from the start of the replay. A playing
+// replay is paused for the seek, see paused
+func (r *ReplayController) SeekFrame(frame int32) error {
+	return r.paused(func() error {
+		err := r.ibt.ReplaySetPlayPosition(RpyPosBegin, frame)
+		if err != nil {
+			return err
+		}
+		return r.confirm(func() bool {
+			return r.is("ReplayFrameNum", float64(frame))
+		}, "ReplayFrameNum")
+	})
+}
+
+// Skip moves the replay frames forward, or back when negative. A playing
+// replay is paused for the skip, see paused
+func (r *ReplayController) Skip(frames int32) error {
+	return r.paused(func() error {
+		before, _ := r.number("ReplayFrameNum")
+		err := r.ibt.ReplaySetPlayPosition(RpyPosCurrent, frames)
+		if err != nil {
+			return err
+		}
+		return r.confirm(func() bool {
+			return r.is("ReplayFrameNum", before+float64(frames))
+		}, "ReplayFrameNum")
+	})
+}
+
+// paused runs a frame seek with the replay paused and plays it again at the
+// speed it had afterwards, even when the seek fails or isn't confirmed.
+// ReplayFrameNum moves on every tick of a playing replay, which would confirm
+// any search and no exact frame
+func (r *ReplayController) paused(seek func() error) error {
+	speed, ok := r.number("ReplayPlaySpeed")
+	if !ok || speed == 0 {
+		return seek()
+	}
+	slow, _ := r.number("ReplayPlaySlowMotion")
+
+	err := r.Pause()
+	if err != nil {
+		return err
+	}
+	err = seek()
+
+	var errPlay error
+	if slow != 0 {
+		errPlay = r.SlowMotion(int(speed))
+	} else {
+		errPlay = r.SetSpeed(int(speed))
+	}
+	if errPlay != nil {
+		return errors.Join(err, errPlay)
+	}
+	return err
+}
+
+// SeekSessionTime jumps to a session time of a session, confirmed once
+// ReplaySessionNum is the session and ReplaySessionTime is within the time
+// tolerance
+func (r *ReplayController) SeekSessionTime(sessionNum int, sessionTime time.Duration) error {
+	err := r.ibt.ReplaySearchSessionTime(sessionNum, sessionTime)
+	if err != nil {
+		return err
+	}
+	return r.confirm(func() bool {
+		t, ok := r.number("ReplaySessionTime")
+		return ok && r.is("ReplaySessionNum", float64(sessionNum)) &&
+			math.Abs(t-sessionTime.Seconds()) <= r.opts.TimeTolerance.Seconds()
+	}, "ReplaySessionNum", "ReplaySessionTime")
+}
+
+// confirm calls Update until done is true or the confirmation timeout expires
+func (r *ReplayController) confirm(done func() bool, vars ...string) error {
+	if r.opts.ConfirmTimeout <= 0 {
+		return nil
+	}
+	for _, name := range vars {
+		if _, ok := r.ibt.Vars.Vars[name]; !ok {
+			return fmt.Errorf("%s is not in the telemetry, the replay can't be confirmed", name)
+		}
+	}
+
+	deadline := time.Now().Add(r.opts.ConfirmTimeout)
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return ErrReplayUnconfirmed
+		}
+
+		state, err := r.ibt.Update(remaining)
+		if err != nil {
+			return fmt.Errorf("failed to confirm replay command: %v", err)
+		}
+		if state != TimedOut && done() {
+			return nil
+		}
+	}
+}
+
+// number reads a variable of the last frame read by Update
+func (r *ReplayController) number(name string) (float64, bool) {
+	if r.ibt.frame == nil {
+		return 0, false
+	}
+	return r.ibt.frameNumber(r.ibt.frame, name)
+}
+
+// is tells if a variable of the last frame has a value
+func (r *ReplayController) is(name string, value float64) bool {
+	v, ok := r.number(name)
+	return ok && v == value
+}
@@ -0,0 +1,277 @@
+package goirsdk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// replayVars is the variable layout of replaySim frames
+var replayVars = []Var{
+	{Type: IRSDK_int, Offset: 0, Count: 1, Name: "ReplayFrameNum"},
+	{Type: IRSDK_int, Offset: 4, Count: 1, Name: "ReplaySessionNum"},
+	{Type: IRSDK_double, Offset: 8, Count: 1, Name: "ReplaySessionTime", Unit: "s"},
+	{Type: IRSDK_int, Offset: 16, Count: 1, Name: "ReplayPlaySpeed"},
+	{Type: IRSDK_bool, Offset: 20, Count: 1, Name: "ReplayPlaySlowMotion"},
+}
+
+// replaySim is a live source acting on the replay broadcast messages, the
+// next frame it publishes shows their effect
+type replaySim struct {
+	*bytes.Reader
+	ignore     bool // ignore publishes frames without acting on the messages
+	lastLap    bool // lastLap doesn't move on next lap searches
+	pending    bool
+	tick       int32
+	frame      int32
+	sessionNum int32
+	speed      int32
+	slow       bool
+}
+
+// newReplaySim creates a replaySim with the headers of a replayVars file
+func newReplaySim(t *testing.T) *replaySim {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "replay.ibt")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer file.Close()
+	headers := TelemetryHeaders{Version: 2, Status: 1, TickRate: 60, BufLen: 24}
+	w, err := NewIBTWriter(file, headers, DiskSubHeader{}, replayVars, []byte(testSessionInfo))
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("Failed to close writer: %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	return &replaySim{Reader: bytes.NewReader(raw)}
+}
+
+func (s *replaySim) Live() bool {
+	return true
+}
+
+// WaitForFrame publishes a frame after a message and every tick while the
+// replay plays
+func (s *replaySim) WaitForFrame(timeout time.Duration) bool {
+	playing := s.speed != 0 && !s.slow && !s.ignore
+	if !s.pending && !playing {
+		time.Sleep(timeout)
+	}
+	return s.pending || playing
+}
+
+func (s *replaySim) ReadFrame(headers *TelemetryHeaders, tick int32) (int32, []byte, error) {
+	s.pending = false
+	s.tick++
+	if !s.slow {
+		s.frame += s.speed
+	}
+
+	frame := make([]byte, headers.BufLen)
+	binary.LittleEndian.PutUint32(frame[0:], uint32(s.frame))
+	binary.LittleEndian.PutUint32(frame[4:], uint32(s.sessionNum))
+	binary.LittleEndian.PutUint64(frame[8:], math.Float64bits(float64(s.frame)/60))
+	binary.LittleEndian.PutUint32(frame[16:], uint32(s.speed))
+	if s.slow {
+		frame[20] = 1
+	}
+	return s.tick, frame, nil
+}
+
+func (s *replaySim) Close() error {
+	return nil
+}
+
+func (s *replaySim) Broadcast(m Msg) error {
+	s.pending = true
+	if s.ignore {
+		return nil
+	}
+
+	value := int32(uint16(m.P2)) | m.P3<<16
+	switch m.Cmd {
+	case BroadcastReplaySetPlaySpeed:
+		s.speed, s.slow = m.P1, m.P2 == 1
+	case BroadcastReplaySearch:
+		if int(m.P1) == RpySrchNextLap && !s.lastLap {
+			s.frame += 3600
+		}
+	case BroadcastReplaySetPlayPosition:
+		if int(m.P1) == RpyPosCurrent {
+			value += s.frame
+		}
+		s.frame = value
+	case BroadcastReplaySearchSessionTime:
+		s.sessionNum, s.frame = m.P1, value*60/1000
+	}
+	return nil
+}
+
+// TestReplayController_Commands
+// Every command waits until the telemetry shows it took effect
+func TestReplayController_Commands(t *testing.T) {
+	// Arrange
+	sim := newReplaySim(t)
+	ibt, err := InitSource(sim, "", "")
+	if err != nil {
+		t.Fatalf("Failed to init from source: %v", err)
+	}
+	replay := NewReplayController(ibt, ReplayOptions{ConfirmTimeout: time.Second})
+
+	// Act
+	steps := []func() error{
+		func() error { return replay.SeekSessionTime(1, 90*time.Second) },
+		func() error { return replay.SlowMotion(2) },
+		func() error { return replay.SeekFrame(100) },
+		func() error { return replay.Skip(-40) },
+		func() error { return replay.NextLap() },
+		func() error { return replay.Pause() },
+	}
+
+	// Assert
+	for k, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("Step %d failed: %v", k, err)
+		}
+	}
+	if got, _ := replay.number("ReplayFrameNum"); got != 3660 {
+		t.Errorf("Expected frame 3660, got %v", got)
+	}
+	if got, _ := replay.number("ReplaySessionNum"); got != 1 {
+		t.Errorf("Expected session 1, got %v", got)
+	}
+}
+
+// TestReplayController_SeekWhilePlaying
+// Seeks pause a playing replay to be confirmed on the frame they reach, then
+// play it again
+func TestReplayController_SeekWhilePlaying(t *testing.T) {
+	// Arrange
+	sim := newReplaySim(t)
+	ibt, err := InitSource(sim, "", "")
+	if err != nil {
+		t.Fatalf("Failed to init from source: %v", err)
+	}
+	replay := NewReplayController(ibt, ReplayOptions{ConfirmTimeout: time.Second})
+	err = replay.Play()
+	if err != nil {
+		t.Fatalf("Failed to play: %v", err)
+	}
+
+	// Act
+	errSeek := replay.SeekFrame(500)
+	afterSeek, _ := replay.number("ReplayFrameNum")
+	errSkip := replay.Skip(-100)
+	afterSkip, _ := replay.number("ReplayFrameNum")
+	errLap := replay.NextLap()
+	afterLap, _ := replay.number("ReplayFrameNum")
+
+	// Assert
+	if errSeek != nil || errSkip != nil || errLap != nil {
+		t.Fatalf("Expected the seeks to be confirmed, got %v, %v and %v", errSeek, errSkip, errLap)
+	}
+	if afterSeek < 500 || afterSeek > 510 {
+		t.Errorf("Expected to play on from frame 500, got %v", afterSeek)
+	}
+	if afterSkip < afterSeek-100 || afterSkip > afterSeek-90 {
+		t.Errorf("Expected to play on 100 frames before %v, got %v", afterSeek, afterSkip)
+	}
+	if afterLap < afterSkip+3600 {
+		t.Errorf("Expected to play on a lap after %v, got %v", afterSkip, afterLap)
+	}
+	if !replay.is("ReplayPlaySpeed", 1) {
+		t.Errorf("Expected the replay to play again")
+	}
+}
+
+// TestReplayController_SeekWhileRewinding
+// A rewinding replay, with a negative ReplayPlaySpeed, rewinds again after a
+// seek
+func TestReplayController_SeekWhileRewinding(t *testing.T) {
+	// Arrange
+	sim := newReplaySim(t)
+	ibt, err := InitSource(sim, "", "")
+	if err != nil {
+		t.Fatalf("Failed to init from source: %v", err)
+	}
+	replay := NewReplayController(ibt, ReplayOptions{ConfirmTimeout: time.Second})
+	err = replay.SetSpeed(-2)
+	if err != nil {
+		t.Fatalf("Failed to rewind: %v", err)
+	}
+
+	// Act
+	err = replay.SeekFrame(500)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected the seek to be confirmed, got %v", err)
+	}
+	if sim.speed != -2 || sim.slow {
+		t.Errorf("Expected the replay to rewind again, got speed %d, slow motion %v", sim.speed, sim.slow)
+	}
+}
+
+// TestReplayController_SeekWhilePlayingUnconfirmed
+// A playing replay plays again after a seek that isn't confirmed
+func TestReplayController_SeekWhilePlayingUnconfirmed(t *testing.T) {
+	// Arrange
+	sim := newReplaySim(t)
+	sim.lastLap = true
+	ibt, err := InitSource(sim, "", "")
+	if err != nil {
+		t.Fatalf("Failed to init from source: %v", err)
+	}
+	replay := NewReplayController(ibt, ReplayOptions{ConfirmTimeout: 20 * time.Millisecond})
+	err = replay.SlowMotion(4)
+	if err != nil {
+		t.Fatalf("Failed to play: %v", err)
+	}
+
+	// Act
+	err = replay.NextLap()
+
+	// Assert
+	if !errors.Is(err, ErrReplayUnconfirmed) {
+		t.Fatalf("Expected ErrReplayUnconfirmed, got %v", err)
+	}
+	if sim.speed != 4 || !sim.slow {
+		t.Errorf("Expected the replay to play again in slow motion 4, got speed %d, slow motion %v", sim.speed, sim.slow)
+	}
+}
+
+// TestReplayController_Unconfirmed
+// Commands the telemetry never reflects time out
+func TestReplayController_Unconfirmed(t *testing.T) {
+	// Arrange
+	sim := newReplaySim(t)
+	sim.ignore = true
+	ibt, err := InitSource(sim, "", "")
+	if err != nil {
+		t.Fatalf("Failed to init from source: %v", err)
+	}
+	replay := NewReplayController(ibt, ReplayOptions{ConfirmTimeout: 20 * time.Millisecond})
+
+	// Act
+	err = replay.SeekSessionTime(0, time.Minute)
+
+	// Assert
+	if !errors.Is(err, ErrReplayUnconfirmed) {
+		t.Fatalf("Expected ErrReplayUnconfirmed, got %v", err)
+	}
+}
@@ -0,0 +1,323 @@
+package goirsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Car picks the entry of the CarIdx variables a condition reads, a car index
+// or one of LeaderCar and PlayerCar. Variables holding a single value ignore it
+type Car int
+
+const (
+	LeaderCar Car = -1 // The car at position 1 of CarIdxPosition
+	PlayerCar Car = -2 // The car of DriverInfo.DriverCarIdx
+)
+
+// ScriptFrame gives conditions the values of the frame Update just read and
+// of the one before it
+type ScriptFrame struct {
+	ibt  *IBT
+	prev []byte
+}
+
+// Value reads a variable of the current frame
+func (f ScriptFrame) Value(name string, car Car) (float64, bool) {
+	return f.read(f.ibt.frame, name, car)
+}
+
+// Previous reads a variable of the previous frame, false on the first one
+func (f ScriptFrame) Previous(name string, car Car) (float64, bool) {
+	return f.read(f.prev, name, car)
+}
+
+// values reads a variable of the previous and the current frame
+func (f ScriptFrame) values(name string, car Car) (float64, float64, bool) {
+	prev, ok := f.Previous(name, car)
+	if !ok {
+		return 0, 0, false
+	}
+	cur, ok := f.Value(name, car)
+	return prev, cur, ok
+}
+
+// read reads an entry of a variable out of a frame
+func (f ScriptFrame) read(frame []byte, name string, car Car) (float64, bool) {
+	v, ok := f.ibt.Vars.Vars[name]
+	if !ok || frame == nil || len(frame) < int(f.ibt.Headers.BufLen) {
+		return 0, false
+	}
+	if v.Count == 1 {
+		return entryNumber(v, frame, 0), true
+	}
+
+	idx, ok := f.carIdx(frame, car)
+	if !ok || idx >= int(v.Count) {
+		return 0, false
+	}
+	return entryNumber(v, frame, int32(idx)), true
+}
+
+// carIdx resolves a Car into a car index for a frame
+func (f ScriptFrame) carIdx(frame []byte, car Car) (int, bool) {
+	switch car {
+	case PlayerCar:
+		if f.ibt.SessionInfo == nil {
+			return 0, false
+		}
+		return f.ibt.SessionInfo.DriverInfo.DriverCarIdx, true
+	case LeaderCar:
+		v, ok := f.ibt.Vars.Vars["CarIdxPosition"]
+		if !ok {
+			return 0, false
+		}
+		for idx := int32(0); idx < v.Count; idx++ {
+			if entryNumber(v, frame, idx) == 1 {
+				return int(idx), true
+			}
+		}
+		return 0, false
+	}
+	return int(car), car >= 0
+}
+
+// Condition tells if a rule of a script should fire on a frame
+type Condition func(f ScriptFrame) bool
+
+// Equals is true while a variable has a value
+func Equals(name string, car Car, value float64) Condition {
+	return func(f ScriptFrame) bool {
+		v, ok := f.Value(name, car)
+		return ok && v == value
+	}
+}
+
+// Above is true while a variable is over a value
+func Above(name string, car Car, value float64) Condition {
+	return func(f ScriptFrame) bool {
+		v, ok := f.Value(name, car)
+		return ok && v > value
+	}
+}
+
+// Below is true while a variable is under a value
+func Below(name string, car Car, value float64) Condition {
+	return func(f ScriptFrame) bool {
+		v, ok := f.Value(name, car)
+		return ok && v < value
+	}
+}
+
+// Crosses is true on the frame a variable goes up past a value
+func Crosses(name string, car Car, value float64) Condition {
+	return func(f ScriptFrame) bool {
+		prev, cur, ok := f.values(name, car)
+		return ok && prev < value && value <= cur
+	}
+}
+
+// CrossesPct is Crosses for the percentages going from 1 back to 0 at the
+// line, like the lap distance ones. A drop of more than half a lap is taken as
+// a wrap around, so CrossesPct("CarIdxLapDistPct", LeaderCar, 0) fires when
+// the leader starts a new lap
+func CrossesPct(name string, car Car, value float64) Condition {
+	return func(f ScriptFrame) bool {
+		prev, cur, ok := f.values(name, car)
+		if !ok {
+			return false
+		}
+		pct := 0 <= prev && prev <= 1 && 0 <= cur && cur <= 1
+		if pct && cur < prev-0.5 {
+			return prev < value || value <= cur
+		}
+		return prev < value && value <= cur
+	}
+}
+
+// SessionStateIs is true while the SessionState is one of the State values
+func SessionStateIs(state int) Condition {
+	return Equals("SessionState", 0, float64(state))
+}
+
+// All is true when every condition is
+func All(conds ...Condition) Condition {
+	return func(f ScriptFrame) bool {
+		for _, c := range conds {
+			if !c(f) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Any is true when one of the conditions is
+func Any(conds ...Condition) Condition {
+	return func(f ScriptFrame) bool {
+		for _, c := range conds {
+			if c(f) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Action is what a rule does when it fires
+type Action func(i *IBT) error
+
+// CameraOnCar focuses the camera on a car number with a named camera group
+func CameraOnCar(carNumber string, group string) Action {
+	return func(i *IBT) error {
+		return NewCameraDirector(i).ShowCar(carNumber, group)
+	}
+}
+
+// CameraOnPosition focuses the camera on a race position with a named group
+func CameraOnPosition(position int, group string) Action {
+	return func(i *IBT) error {
+		return NewCameraDirector(i).ShowPosition(position, group)
+	}
+}
+
+// CameraOnLeader focuses the camera on the leader with a named group
+func CameraOnLeader(group string) Action {
+	return func(i *IBT) error {
+		return NewCameraDirector(i).ShowLeader(group)
+	}
+}
+
+// CameraOnIncident focuses the camera on the last incident with a named group
+func CameraOnIncident(group string) Action {
+	return func(i *IBT) error {
+		return NewCameraDirector(i).ShowIncident(group)
+	}
+}
+
+// Telemetry starts, stops or restarts the telemetry recording with a
+// TelemCommand mode
+func Telemetry(mode int) Action {
+	return func(i *IBT) error {
+		return i.TelemCommand(mode)
+	}
+}
+
+// SendMsg sends a raw broadcast message
+func SendMsg(m Msg) Action {
+	return func(i *IBT) error {
+		return i.broadcast(m.Cmd, int(m.P1), int(m.P2), int(m.P3))
+	}
+}
+
+// rule is a condition with the actions to run when it becomes true
+type rule struct {
+	cond    Condition
+	actions []Action
+	once    bool
+	fired   bool // fired is set once the rule ran
+	was     bool // was is the condition on the previous frame
+}
+
+// Script is a list of rules run on the frames read by Update. A rule fires on
+// the frame its condition becomes true, not on every frame it stays true:
+//
+//	script := NewScript().
+//		When(Crosses("CarIdxLapDistPct", LeaderCar, 0)).Do(CameraOnLeader("Blimp")).
+//		When(SessionStateIs(StateRacing)).Once().Do(Telemetry(TelemCommandStart))
+type Script struct {
+	rules []*rule
+	ibt   *IBT   // IBT of the last step, a new one starts over
+	prev  []byte // Frame of the last step
+}
+
+// NewScript creates an empty script
+func NewScript() *Script {
+	return &Script{}
+}
+
+// ScriptRule is a rule being built, Do adds it to its script
+type ScriptRule struct {
+	script *Script
+	rule   rule
+}
+
+// When starts a rule firing when cond becomes true
+func (s *Script) When(cond Condition) *ScriptRule {
+	return &ScriptRule{script: s, rule: rule{cond: cond}}
+}
+
+// Once makes the rule fire only the first time
+func (r *ScriptRule) Once() *ScriptRule {
+	r.rule.once = true
+	return r
+}
+
+// Do sets the actions of the rule, run in order, and adds it to the script
+func (r *ScriptRule) Do(actions ...Action) *Script {
+	r.rule.actions = actions
+	r.script.rules = append(r.script.rules, &r.rule)
+	return r.script
+}
+
+// Step checks the rules against the frame the last Update read and runs the
+// actions of those firing. Every action runs, the errors are joined
+func (s *Script) Step(i *IBT) error {
+	if i.frame == nil {
+		return nil
+	}
+	if i != s.ibt {
+		// A new session, the previous frame and conditions don't carry over
+		s.ibt = i
+		s.prev = nil
+		for _, r := range s.rules {
+			r.was = false
+		}
+	}
+
+	f := ScriptFrame{ibt: i, prev: s.prev}
+	var errs []error
+	for _, r := range s.rules {
+		now := r.cond(f)
+		fire := now && !r.was && !(r.once && r.fired)
+		r.was = now
+		if !fire {
+			continue
+		}
+
+		r.fired = true
+		for _, a := range r.actions {
+			err := a(i)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	s.prev = i.frame
+
+	return errors.Join(errs...)
+}
+
+// Run calls Update and Step until ctx is done or the data ends. onError gets
+// the errors of the actions, which don't stop the script, and may be nil
+func (s *Script) Run(ctx context.Context, i *IBT, timeout time.Duration, onError func(error)) error {
+	for ctx.Err() == nil {
+		state, err := i.Update(timeout)
+		if err != nil {
+			return fmt.Errorf("failed to update: %v", err)
+		}
+		if state == Ended {
+			return nil
+		}
+		if state != Running {
+			continue
+		}
+
+		err = s.Step(i)
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}
+	return ctx.Err()
+}
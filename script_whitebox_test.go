@@ -0,0 +1,150 @@
+package goirsdk
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestScript_Run
+// Rules fire on the frame their condition becomes true, once rules only the
+// first time
+func TestScript_Run(t *testing.T) {
+	// Arrange
+	ibt := openTestIBT(t, writeTestIBT(t, 200))
+	rec := &RecordingTransport{}
+	ibt.SetBroadcastTransport(rec)
+	lapMsg := Msg{Cmd: BroadcastReplaySearch, P1: int32(RpySrchNextLap)}
+	script := NewScript().
+		When(Above("Lap", 0, 0)).Do(SendMsg(lapMsg)).
+		When(Crosses("CarIdxLap", Car(1), 2.5)).Do(CameraOnCar("7", "TV1")).
+		When(Any(Equals("SessionNum", 0, 1), Below("Speed", 0, 0))).Once().Do(Telemetry(TelemCommandStart))
+
+	// Act
+	err := script.Run(context.Background(), ibt, 0, func(err error) {
+		t.Errorf("Unexpected action error: %v", err)
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to run script: %v", err)
+	}
+	expected := []Msg{
+		lapMsg,
+		{Cmd: BroadcastCamSwitchNum, P1: 7, P2: 10},
+		{Cmd: BroadcastTelemCommand, P1: int32(TelemCommandStart)},
+	}
+	if diff := cmp.Diff(expected, rec.Messages()); diff != "" {
+		t.Errorf("Messages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestCrosses
+// Crossing a value counts going up past it, a drop never does
+func TestCrosses(t *testing.T) {
+	ibt := openTestIBT(t, writeTestIBT(t, 1))
+
+	tests := map[string]struct {
+		prev, cur float32
+		value     float64
+		expected  bool
+	}{
+		"up past":         {prev: 0.4, cur: 0.6, value: 0.5, expected: true},
+		"onto the value":  {prev: 0.4, cur: 0.5, value: 0.5, expected: true},
+		"below":           {prev: 0.1, cur: 0.3, value: 0.5, expected: false},
+		"down past":       {prev: 0.6, cur: 0.4, value: 0.5, expected: false},
+		"drop at line":    {prev: 0.99, cur: 0.01, value: 0, expected: false},
+		"large drop":      {prev: 6500, cur: 5000, value: 7000, expected: false},
+		"large drop to 0": {prev: 6500, cur: 5000, value: 0, expected: false},
+		"large rise":      {prev: 5000, cur: 7500, value: 7000, expected: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			ibt.frame = speedFrame(tt.cur)
+			f := ScriptFrame{ibt: ibt, prev: speedFrame(tt.prev)}
+
+			// Act
+			got := Crosses("Speed", 0, tt.value)(f)
+
+			// Assert
+			if got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestCrossesPct
+// Crossing a percentage counts going up past it and wrapping around it, but
+// only values within 0 and 1 wrap
+func TestCrossesPct(t *testing.T) {
+	ibt := openTestIBT(t, writeTestIBT(t, 1))
+
+	tests := map[string]struct {
+		prev, cur float32
+		value     float64
+		expected  bool
+	}{
+		"up past":         {prev: 0.4, cur: 0.6, value: 0.5, expected: true},
+		"down past":       {prev: 0.6, cur: 0.4, value: 0.5, expected: false},
+		"wrap at line":    {prev: 0.99, cur: 0.01, value: 0, expected: true},
+		"wrap before":     {prev: 0.95, cur: 0.02, value: 0.97, expected: true},
+		"wrap elsewhere":  {prev: 0.95, cur: 0.02, value: 0.5, expected: false},
+		"after the value": {prev: 0.2, cur: 0.3, value: 0, expected: false},
+		"large drop":      {prev: 6500, cur: 5000, value: 7000, expected: false},
+		"not on track":    {prev: 0.9, cur: -1, value: 0, expected: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			ibt.frame = speedFrame(tt.cur)
+			f := ScriptFrame{ibt: ibt, prev: speedFrame(tt.prev)}
+
+			// Act
+			got := CrossesPct("Speed", 0, tt.value)(f)
+
+			// Assert
+			if got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// speedFrame is a frame of the test IBT with a Speed
+func speedFrame(speed float32) []byte {
+	frame := make([]byte, testBufLen)
+	binary.LittleEndian.PutUint32(frame[16:], math.Float32bits(speed))
+	return frame
+}
+
+// TestScript_NewSession
+// A new IBT, after a reconnection, doesn't see the frames of the old one
+func TestScript_NewSession(t *testing.T) {
+	// Arrange
+	rec := &RecordingTransport{}
+	script := NewScript().When(Crosses("Speed", 0, 0.5)).Do(Telemetry(TelemCommandRestart))
+	first := openTestIBT(t, writeTestIBT(t, 1))
+	second := openTestIBT(t, writeTestIBT(t, 2))
+	first.SetBroadcastTransport(rec)
+	second.SetBroadcastTransport(rec)
+
+	// Act
+	first.Update(time.Second)
+	script.Step(first)
+	second.Update(time.Second)
+	second.Update(time.Second)
+	script.Step(second)
+
+	// Assert
+	if got := len(rec.Messages()); got != 0 {
+		t.Errorf("Expected no message across sessions, got %d", got)
+	}
+}
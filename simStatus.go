@@ -0,0 +1,133 @@
+package goirsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// SimStatus tells how far the simulator is from publishing telemetry
+type SimStatus int
+
+const (
+	SimUnknown    SimStatus = iota // The status wasn't checked yet
+	SimNotRunning                  // iRacing isn't running
+	SimRunning                     // iRacing runs, but the driver isn't in the car
+	SimInCar                       // The driver is in the car, on track
+)
+
+func (s SimStatus) String() string {
+	switch s {
+	case SimUnknown:
+		return "unknown"
+	case SimNotRunning:
+		return "sim not running"
+	case SimRunning:
+		return "not in car"
+	case SimInCar:
+		return "in car"
+	}
+	return fmt.Sprintf("SimStatus(%d)", int(s))
+}
+
+// SimStatusClient asks the web server of iRacing whether the simulator runs,
+// which is cheaper than opening the memory map to find out
+type SimStatusClient struct {
+	URL  string       // URL of the status, SimStatusUrl by default
+	HTTP *http.Client // HTTP client of the requests
+}
+
+// simStatusTimeout bounds a status request, the web server answers well within
+const simStatusTimeout = time.Second
+
+// NewSimStatusClient creates a client for url, SimStatusUrl when empty, with
+// a timeout of a second per request
+func NewSimStatusClient(url string) *SimStatusClient {
+	if url == "" {
+		url = SimStatusUrl
+	}
+	return &SimStatusClient{URL: url, HTTP: &http.Client{Timeout: simStatusTimeout}}
+}
+
+// Status returns SimRunning or SimNotRunning. The web server only answers
+// while iRacing runs, so a refused connection means it's not running. Other
+// failures, a timeout included, return SimUnknown and an error
+func (c *SimStatusClient) Status(ctx context.Context) (SimStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return SimUnknown, fmt.Errorf("invalid sim status URL: %v", err)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		if connectionRefused(err) {
+			return SimNotRunning, nil
+		}
+		return SimUnknown, fmt.Errorf("failed to get sim status: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return SimUnknown, fmt.Errorf("unexpected sim status answer: %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return SimUnknown, fmt.Errorf("failed to read sim status: %v", err)
+	}
+
+	return parseSimStatus(string(body))
+}
+
+// connectionRefused tells if a request failed because nothing listens on the
+// address. The error codes differ between OSes, so any dial error that isn't a
+// timeout counts
+func connectionRefused(err error) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial" && !opErr.Timeout()
+}
+
+// parseSimStatus reads the running field of the status, "running:1" when the
+// simulator runs
+func parseSimStatus(body string) (SimStatus, error) {
+	for _, field := range strings.Fields(body) {
+		value, ok := strings.CutPrefix(field, "running:")
+		if !ok {
+			continue
+		}
+		if value == "1" {
+			return SimRunning, nil
+		}
+		return SimNotRunning, nil
+	}
+	return SimUnknown, fmt.Errorf("no running field in sim status %q", body)
+}
+
+// SimStatusHandler answers like the web server of iRacing, to stand in for it
+// in tests or next to the Emulator
+type SimStatusHandler struct {
+	running atomic.Bool
+}
+
+// SetRunning sets the status answered
+func (h *SimStatusHandler) SetRunning(running bool) {
+	h.running.Store(running)
+}
+
+func (h *SimStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	running := 0
+	if h.running.Load() {
+		running = 1
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprintf(w, "running:%d\n", running)
+}
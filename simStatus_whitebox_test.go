@@ -0,0 +1,133 @@
+package goirsdk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestSimStatusClient_Status
+// The client reads the running field and takes an unreachable server for a
+// simulator that isn't running
+func TestSimStatusClient_Status(t *testing.T) {
+	// Arrange
+	handler := &SimStatusHandler{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "garbage")
+	}))
+	defer broken.Close()
+	gone := httptest.NewServer(handler)
+	gone.Close()
+	ctx := context.Background()
+
+	// Act
+	stopped, errStopped := NewSimStatusClient(server.URL).Status(ctx)
+	handler.SetRunning(true)
+	running, errRunning := NewSimStatusClient(server.URL).Status(ctx)
+	unreachable, errUnreachable := NewSimStatusClient(gone.URL).Status(ctx)
+	_, errBroken := NewSimStatusClient(broken.URL).Status(ctx)
+
+	// Assert
+	if stopped != SimNotRunning || errStopped != nil {
+		t.Errorf("Expected not running, got %v (%v)", stopped, errStopped)
+	}
+	if running != SimRunning || errRunning != nil {
+		t.Errorf("Expected running, got %v (%v)", running, errRunning)
+	}
+	if unreachable != SimNotRunning || errUnreachable != nil {
+		t.Errorf("Expected not running, got %v (%v)", unreachable, errUnreachable)
+	}
+	if errBroken == nil {
+		t.Errorf("Expected an error for an answer without a running field")
+	}
+}
+
+// TestConnection_SimStatus
+// The memory map is only opened once the status server says iRacing runs and
+// the connection tells not running, running and in car apart
+func TestConnection_SimStatus(t *testing.T) {
+	// Arrange
+	handler := &SimStatusHandler{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	raw, err := os.ReadFile(writeTestIBT(t, 1))
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	opened := 0
+	conn := NewConnection(ConnectionOptions{
+		RetryInterval: time.Millisecond,
+		Status:        NewSimStatusClient(server.URL),
+		Open: func() (TelemetrySource, error) {
+			opened++
+			return &memorySource{Reader: bytes.NewReader(raw), frames: [][]byte{testFrame(0)}}, nil
+		},
+	})
+	defer conn.Close()
+
+	// Act
+	conn.Update(10 * time.Millisecond)
+	notRunning := conn.SimStatus()
+	openedBefore := opened
+	handler.SetRunning(true)
+	state, err := conn.Update(10 * time.Millisecond)
+
+	// Assert
+	if err != nil || state != Running {
+		t.Fatalf("Expected a running session, got %v (%v)", state, err)
+	}
+	if notRunning != SimNotRunning || openedBefore != 0 {
+		t.Errorf("Expected the memory map untouched while not running, got %v after %d opens",
+			notRunning, openedBefore)
+	}
+	if conn.SimStatus() != SimInCar {
+		t.Errorf("Expected in car, got %v", conn.SimStatus())
+	}
+}
+
+// TestConnection_SlowSimStatus
+// A status server slower than the Update timeout doesn't keep the connection
+// from opening the memory map
+func TestConnection_SlowSimStatus(t *testing.T) {
+	// Arrange
+	handler := &SimStatusHandler{}
+	handler.SetRunning(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	raw, err := os.ReadFile(writeTestIBT(t, 1))
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	conn := NewConnection(ConnectionOptions{
+		RetryInterval: time.Millisecond,
+		Status:        NewSimStatusClient(server.URL),
+		Open: func() (TelemetrySource, error) {
+			return &memorySource{Reader: bytes.NewReader(raw), frames: [][]byte{testFrame(0)}}, nil
+		},
+	})
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	// Act
+	state, err := conn.Update(time.Millisecond)
+	timedOut, errTimedOut := NewSimStatusClient(server.URL).Status(ctx)
+
+	// Assert
+	if err != nil || state != Running {
+		t.Fatalf("Expected a running session, got %v (%v)", state, err)
+	}
+	if timedOut != SimUnknown || errTimedOut == nil {
+		t.Errorf("Expected an unknown status on a timeout, got %v (%v)", timedOut, errTimedOut)
+	}
+}
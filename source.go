@@ -0,0 +1,254 @@
+package goirsdk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/ESilva15/goirsdk/winutils"
+)
+
+const (
+	statusOffset            = 4  // Offset of Status in the header
+	sessionInfoUpdateOffset = 12 // Offset of SessionInfoUpdate in the header
+	numBufOffset            = 32 // Offset of NumBuf in the header
+	varBufOffset            = 48 // Offset of the first var buffer in the header
+	varBufSize              = 16 // Size of each var buffer entry in the header
+
+	// tickPollInterval is how often the tick counts are checked when there is
+	// no data valid event to wait on
+	tickPollInterval = time.Millisecond
+
+	// maxReadTries is how many times a live frame is read before giving up
+	// on getting a copy iRacing didn't overwrite midway
+	maxReadTries = 3
+)
+
+// ErrTornRead means every copy of a live frame was overwritten while it was
+// being read
+var ErrTornRead = errors.New("the frame was overwritten while being read")
+
+// TelemetrySource is where an IBT reads its data from: a telemetry file, the
+// memory map of a live session or anything laid out like them. Update only
+// talks to its source, so new sources plug in through InitSource
+type TelemetrySource interface {
+	// ReadAt reads the headers, the variable headers and the session info at
+	// the offsets of an IBT file or memory map
+	io.ReaderAt
+	// Live tells if the frames come from a running session, where only the
+	// latest frame can be read
+	Live() bool
+	// WaitForFrame blocks until a frame newer than the last one read is
+	// available or the timeout expires, returning false on timeout. Files
+	// always have their next frame ready
+	WaitForFrame(timeout time.Duration) bool
+	// ReadFrame reads the frame at tick of a file, or the latest frame of a
+	// live session, and returns the tick to store in Vars.Tick: the next tick
+	// for files and the tick count of the frame for live sessions. Files
+	// return io.EOF past their last frame
+	ReadFrame(headers *TelemetryHeaders, tick int32) (int32, []byte, error)
+	// Close releases what the source holds
+	Close() error
+}
+
+// fileSource reads the frames of a telemetry file in order
+type fileSource struct {
+	io.ReaderAt
+}
+
+// NewFileSource wraps a telemetry file. Closing the source leaves the file
+// open, it belongs to the caller
+func NewFileSource(r io.ReaderAt) TelemetrySource {
+	return fileSource{r}
+}
+
+func (s fileSource) Live() bool {
+	return false
+}
+
+func (s fileSource) WaitForFrame(timeout time.Duration) bool {
+	return true
+}
+
+func (s fileSource) ReadFrame(headers *TelemetryHeaders, tick int32) (int32, []byte, error) {
+	buf := make([]byte, headers.BufLen)
+	_, err := s.ReadAt(buf, int64(headers.BufOffset)+int64(tick)*int64(headers.BufLen))
+	if err != nil {
+		return tick, nil, err
+	}
+
+	return tick + 1, buf, nil
+}
+
+func (s fileSource) Close() error {
+	return nil
+}
+
+// liveSource reads the memory map iRacing publishes during a session
+type liveSource struct {
+	mem      winutils.Reader
+	winUtils *winutils.IRacingWinUtils // WinUtils gives access to the system utilities
+	hasEvent bool                      // hasEvent is false when the tick counts have to be polled
+	lastTick int32                     // lastTick is the tick count of the last frame read
+	retries  atomic.Int64              // retries counts the frames read again after a torn read
+}
+
+// OpenLiveSource opens the memory map, data valid event and broadcast channel
+// of a live session
+func OpenLiveSource() (TelemetrySource, error) {
+	mem, err := winutils.OpenMemMap(IRSDK_MEMMAPFILENAME, fileMapSize)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open memory mapped file: %v", err)
+	}
+
+	// To use our windows interface we need to initialize it first
+	// it will return a struct with a pointer to the windows handles
+	// if, for some reason, we need to stub out this to run in on Linux its easier
+	utils, err := winutils.Init()
+	if err != nil {
+		mem.Close()
+		return nil, err
+	}
+	s := liveSource{mem: mem, winUtils: utils}
+
+	// We need to open the windows event thing, without it the tick counts
+	// are polled instead
+	err = utils.OpenWinEvent(IRSDK_DATAVALIDEVENTNAME)
+	s.hasEvent = err == nil
+
+	// We need to open the broadcast channel
+	err = utils.OpenBroadcastChannel(IRSDK_BROADCASTMSGNAME)
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (s *liveSource) ReadAt(p []byte, off int64) (int, error) {
+	return s.mem.ReadAt(p, off)
+}
+
+func (s *liveSource) Live() bool {
+	return true
+}
+
+// WaitForFrame waits until the latest tick count differs from the one of the
+// last frame read, waking up on the data valid event when there is one
+func (s *liveSource) WaitForFrame(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		_, vb, err := s.latest()
+		if err == nil && vb.TickCount > 0 && vb.TickCount != s.lastTick {
+			return true
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return false
+		}
+		if s.hasEvent {
+			s.winUtils.CheckValidDataEvent(remaining)
+		} else {
+			time.Sleep(min(tickPollInterval, remaining))
+		}
+	}
+}
+
+// latest returns the index and var buffer with the highest tick count, the
+// one iRacing wrote last
+func (s *liveSource) latest() (int, varBuffer, error) {
+	var raw [4]byte
+	_, err := s.mem.ReadAt(raw[:], numBufOffset)
+	if err != nil {
+		return 0, varBuffer{}, err
+	}
+	numBuf := int(int32(binary.LittleEndian.Uint32(raw[:])))
+
+	var latest int
+	var vb varBuffer
+	for k := 0; k < numBuf; k++ {
+		curVb, err := s.varBuffer(k)
+		if err != nil {
+			return 0, varBuffer{}, err
+		}
+
+		if vb.TickCount < curVb.TickCount {
+			latest = k
+			vb = curVb
+		}
+	}
+
+	return latest, vb, nil
+}
+
+// varBuffer reads the entry k of the var buffers table
+func (s *liveSource) varBuffer(k int) (varBuffer, error) {
+	rbuf := make([]byte, varBufSize)
+	_, err := s.mem.ReadAt(rbuf, int64(varBufOffset+k*varBufSize))
+	if err != nil {
+		return varBuffer{}, err
+	}
+
+	var vb varBuffer
+	err = binary.Read(bytes.NewBuffer(rbuf[:]), binary.LittleEndian, &vb)
+	if err != nil {
+		return varBuffer{}, err
+	}
+	return vb, nil
+}
+
+// ReadFrame reads the frame of the var buffer iRacing wrote last. iRacing
+// keeps writing while we copy, so the tick count of the buffer is checked
+// again after the copy and the frame read again if it changed
+func (s *liveSource) ReadFrame(headers *TelemetryHeaders, tick int32) (int32, []byte, error) {
+	buf := make([]byte, headers.BufLen)
+	for try := 0; try < maxReadTries; try++ {
+		if try > 0 {
+			s.retries.Add(1)
+		}
+
+		k, vb, err := s.latest()
+		if err != nil {
+			return tick, nil, err
+		}
+
+		_, err = s.mem.ReadAt(buf, int64(vb.BufOffset))
+		if err != nil {
+			return tick, nil, err
+		}
+
+		after, err := s.varBuffer(k)
+		if err != nil {
+			return tick, nil, err
+		}
+		if after.TickCount == vb.TickCount {
+			s.lastTick = vb.TickCount
+			return vb.TickCount, buf, nil
+		}
+	}
+
+	return tick, nil, ErrTornRead
+}
+
+// ReadRetries returns how many times a frame was read again because iRacing
+// overwrote it during the copy
+func (s *liveSource) ReadRetries() int64 {
+	return s.retries.Load()
+}
+
+// Broadcast sends a message trough the broadcast channel of the simulator
+func (s *liveSource) Broadcast(m Msg) error {
+	wParam, lParam := m.Params()
+	return s.winUtils.SendBroadcastMessage(wParam, lParam)
+}
+
+func (s *liveSource) Close() error {
+	s.winUtils.Close()
+	return s.mem.Close()
+}
@@ -0,0 +1,79 @@
+package goirsdk
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+// memorySource serves the frames of a telemetry file held in memory as if
+// they were published by a live session
+type memorySource struct {
+	*bytes.Reader
+	frames [][]byte
+	closed bool
+}
+
+func (s *memorySource) Live() bool {
+	return true
+}
+
+func (s *memorySource) WaitForFrame(timeout time.Duration) bool {
+	return len(s.frames) > 0
+}
+
+func (s *memorySource) ReadFrame(headers *TelemetryHeaders, tick int32) (int32, []byte, error) {
+	if len(s.frames) == 0 {
+		return tick, nil, io.EOF
+	}
+	frame := s.frames[0]
+	s.frames = s.frames[1:]
+	return 1000 + int32(3-len(s.frames)), frame, nil
+}
+
+func (s *memorySource) Close() error {
+	s.closed = true
+	return nil
+}
+
+// TestInitSource_CustomSource
+// Update reads its frames from whichever source it was initialized with
+func TestInitSource_CustomSource(t *testing.T) {
+	// Arrange
+	raw, err := os.ReadFile(writeTestIBT(t, 1))
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	src := &memorySource{
+		Reader: bytes.NewReader(raw),
+		frames: [][]byte{testFrame(30), testFrame(60), testFrame(90)},
+	}
+
+	// Act
+	ibt, err := InitSource(src, "", "")
+	if err != nil {
+		t.Fatalf("Failed to init from source: %v", err)
+	}
+	_, err = ibt.Update(0)
+	if err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+	_, err = ibt.Update(0)
+	if err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+	ibt.Close()
+
+	// Assert
+	if ibt.Vars.Tick != 1002 {
+		t.Fatalf("Expected the tick of the source, got %d", ibt.Vars.Tick)
+	}
+	if got := ibt.Vars.Vars["Lap"].Value.(int); got != 1 {
+		t.Fatalf("Expected lap 1 from the second frame, got %d", got)
+	}
+	if !src.closed {
+		t.Fatalf("Expected Close to close the source")
+	}
+}
@@ -0,0 +1,69 @@
+package goirsdk
+
+import (
+	"time"
+)
+
+// UpdateStats are running statistics of the frames read by Update, to tell
+// when a consumer falls behind the simulator
+type UpdateStats struct {
+	Frames        int64         // Frames decoded by Update
+	SkippedTicks  int64         // Live ticks published but never read
+	DroppedReads  int64         // Updates that skipped at least one live tick
+	LateReads     int64         // Updates called more than a tick after the previous one returned
+	Timeouts      int64         // Updates that returned TimedOut
+	ReadRetries   int64         // Live frames read again after a torn read
+	DecodeTime    time.Duration // Time spent decoding frames
+	MaxDecodeTime time.Duration // Longest time spent decoding a frame
+}
+
+// AvgDecodeTime returns the average time spent decoding a frame
+func (s UpdateStats) AvgDecodeTime() time.Duration {
+	if s.Frames == 0 {
+		return 0
+	}
+	return s.DecodeTime / time.Duration(s.Frames)
+}
+
+// DropRate returns the fraction of the published live ticks that were skipped
+func (s UpdateStats) DropRate() float64 {
+	if s.Frames+s.SkippedTicks == 0 {
+		return 0
+	}
+	return float64(s.SkippedTicks) / float64(s.Frames+s.SkippedTicks)
+}
+
+// Stats returns the statistics of the frames read by Update since Init or
+// the last ResetStats
+func (i *IBT) Stats() UpdateStats {
+	stats := i.stats
+	stats.ReadRetries = i.ReadRetries() - i.statsRetries
+	return stats
+}
+
+// ResetStats starts the statistics over
+func (i *IBT) ResetStats() {
+	i.stats = UpdateStats{}
+	i.statsRetries = i.ReadRetries()
+}
+
+// tickPeriod returns the time between two ticks
+func (i *IBT) tickPeriod() time.Duration {
+	if i.Headers.TickRate <= 0 {
+		return 0
+	}
+	return time.Second / time.Duration(i.Headers.TickRate)
+}
+
+// countSkipped records the live ticks published between the last frame read
+// and tick, a tick count going back means the session restarted
+func (i *IBT) countSkipped(tick int32) {
+	i.Vars.SkippedTicks = 0
+	if !i.source.Live() || i.Vars.Tick <= 0 || tick <= i.Vars.Tick+1 {
+		return
+	}
+
+	i.Vars.SkippedTicks = tick - i.Vars.Tick - 1
+	i.stats.SkippedTicks += int64(i.Vars.SkippedTicks)
+	i.stats.DroppedReads++
+}
@@ -0,0 +1,219 @@
+package goirsdk
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// triggerKind is the rule of a Trigger
+type triggerKind int
+
+const (
+	triggerEveryTick triggerKind = iota
+	triggerOnChange
+	triggerOnCross
+	triggerAtRate
+)
+
+// Trigger tells on which frames a Subscription is notified
+type Trigger struct {
+	kind  triggerKind
+	level float64 // level crossed by OnCross
+	hz    float64 // rate of AtRate
+}
+
+// EveryTick notifies on every frame Update reads
+func EveryTick() Trigger {
+	return Trigger{kind: triggerEveryTick}
+}
+
+// OnChange notifies on the first frame and then whenever the value changes
+func OnChange() Trigger {
+	return Trigger{kind: triggerOnChange}
+}
+
+// OnCross notifies when a single numeric value goes past level, upwards or
+// downwards. Going up it fires once the value reaches level
+func OnCross(level float64) Trigger {
+	return Trigger{kind: triggerOnCross, level: level}
+}
+
+// AtRate notifies hz times per second of telemetry, counted in ticks so files
+// read faster than real time are sampled the same
+func AtRate(hz float64) Trigger {
+	return Trigger{kind: triggerAtRate, hz: hz}
+}
+
+// VarEvent is the notification of a Subscription
+type VarEvent struct {
+	Name     string
+	Tick     int32       // Vars.Tick once the frame was read
+	Value    interface{} // Value of the variable, with the types of Var.Value
+	Previous interface{} // Value on the previous frame, nil on the first one
+}
+
+// Subscription delivers the values of a variable to a callback or a channel
+// from Update, see IBT.Subscribe and IBT.SubscribeChan
+type Subscription struct {
+	ibt      *IBT
+	name     string
+	trigger  Trigger
+	interval int32 // interval is the number of ticks between AtRate notifications
+
+	fn func(VarEvent)
+	ch chan VarEvent
+
+	mu     sync.Mutex // mu guards ch and closed
+	closed bool
+
+	// State of the trigger, only touched by Update
+	prev     interface{}
+	started  bool
+	lastTick int32
+}
+
+// subscriptions are the subscriptions of an IBT
+type subscriptions struct {
+	mu   sync.Mutex
+	list []*Subscription
+}
+
+// Subscribe calls fn from Update with the value of a variable on the frames
+// the trigger picks. fn runs on the goroutine calling Update
+func (i *IBT) Subscribe(name string, trigger Trigger, fn func(VarEvent)) (*Subscription, error) {
+	if fn == nil {
+		return nil, fmt.Errorf("no callback given for %s", name)
+	}
+	return i.subscribe(name, trigger, fn, nil)
+}
+
+// SubscribeChan sends the value of a variable on the frames the trigger picks
+// to a channel holding up to size events. Events are dropped while the channel
+// is full so Update never blocks. Unsubscribe closes the channel
+func (i *IBT) SubscribeChan(name string, trigger Trigger, size int) (*Subscription, <-chan VarEvent, error) {
+	if size < 0 {
+		return nil, nil, fmt.Errorf("invalid channel size %d for %s", size, name)
+	}
+	ch := make(chan VarEvent, size)
+	s, err := i.subscribe(name, trigger, nil, ch)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, ch, nil
+}
+
+// subscribe validates and registers a subscription
+func (i *IBT) subscribe(name string, trigger Trigger, fn func(VarEvent), ch chan VarEvent) (*Subscription, error) {
+	v, ok := i.Vars.Vars[name]
+	if !ok {
+		return nil, fmt.Errorf("variable %s does not exist", name)
+	}
+
+	s := &Subscription{ibt: i, name: name, trigger: trigger, fn: fn, ch: ch}
+	switch trigger.kind {
+	case triggerOnCross:
+		// Entries created by the bitfield parsing are booleans
+		numeric := v.Count == 0 || (v.Count == 1 && v.Type != IRSDK_char && v.Type != IRSDK_bitField)
+		if !numeric {
+			return nil, fmt.Errorf("variable %s is not a single number", name)
+		}
+	case triggerAtRate:
+		if trigger.hz <= 0 {
+			return nil, fmt.Errorf("invalid rate %v for %s", trigger.hz, name)
+		}
+		rate := i.Headers.TickRate
+		if rate <= 0 {
+			rate = 60
+		}
+		s.interval = max(1, int32(math.Round(float64(rate)/trigger.hz)))
+	}
+
+	i.subs.mu.Lock()
+	i.subs.list = append(i.subs.list, s)
+	i.subs.mu.Unlock()
+
+	return s, nil
+}
+
+// Unsubscribe stops the notifications, it can be called from a callback
+func (s *Subscription) Unsubscribe() {
+	s.ibt.subs.mu.Lock()
+	s.ibt.subs.list = slices.DeleteFunc(s.ibt.subs.list, func(o *Subscription) bool { return o == s })
+	s.ibt.subs.mu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	if s.ch != nil {
+		close(s.ch)
+	}
+}
+
+// dispatchSubscriptions notifies the subscriptions of the frame Update just
+// read
+func (i *IBT) dispatchSubscriptions() {
+	i.subs.mu.Lock()
+	list := slices.Clone(i.subs.list)
+	i.subs.mu.Unlock()
+
+	for _, s := range list {
+		v, ok := i.Vars.Vars[s.name]
+		if !ok {
+			continue
+		}
+		if s.fires(v.Value, i.Vars.Tick) {
+			s.deliver(VarEvent{Name: s.name, Tick: i.Vars.Tick, Value: v.Value, Previous: s.prev})
+		}
+		s.prev = v.Value
+		s.started = true
+	}
+}
+
+// fires tells if the trigger picks a frame, updating its state
+func (s *Subscription) fires(value interface{}, tick int32) bool {
+	switch s.trigger.kind {
+	case triggerOnChange:
+		return !s.started || !reflect.DeepEqual(s.prev, value)
+	case triggerOnCross:
+		if !s.started {
+			return false
+		}
+		prev, okPrev := toNumber(s.prev)
+		cur, okCur := toNumber(value)
+		return okPrev && okCur && (prev < s.trigger.level) != (cur < s.trigger.level)
+	case triggerAtRate:
+		// Ticks going back are a new session or a replay being rewound
+		if s.started && tick >= s.lastTick && tick-s.lastTick < s.interval {
+			return false
+		}
+		s.lastTick = tick
+		return true
+	}
+	return true
+}
+
+// deliver hands an event to the callback or the channel
+func (s *Subscription) deliver(e VarEvent) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	if s.ch != nil {
+		select {
+		case s.ch <- e:
+		default:
+		}
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+
+	s.fn(e)
+}
@@ -0,0 +1,91 @@
+package goirsdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestSubscribe_Triggers
+// Update notifies the subscriptions on the ticks their trigger picks. Files
+// set Vars.Tick to the next tick, so frame 0 is notified as tick 1
+func TestSubscribe_Triggers(t *testing.T) {
+	tests := []struct {
+		name     string
+		variable string
+		trigger  Trigger
+		expected []int32
+	}{
+		{name: "Every tick", variable: "Speed", trigger: EveryTick(), expected: []int32{1, 2, 3, 4, 5}},
+		{name: "On change", variable: "Lap", trigger: OnChange(), expected: []int32{1, 61, 121}},
+		{name: "On cross", variable: "Speed", trigger: OnCross(100), expected: []int32{101}},
+		{name: "At rate", variable: "Speed", trigger: AtRate(2), expected: []int32{1, 31, 61, 91, 121}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			ibt := openTestIBT(t, writeTestIBT(t, 150))
+			var ticks []int32
+			_, err := ibt.Subscribe(test.variable, test.trigger, func(e VarEvent) {
+				if len(ticks) < 5 {
+					ticks = append(ticks, e.Tick)
+				}
+			})
+			if err != nil {
+				t.Fatalf("Failed to subscribe: %v", err)
+			}
+
+			// Act
+			for state, _ := ibt.Update(time.Second); state == Running; state, _ = ibt.Update(time.Second) {
+			}
+
+			// Assert
+			if diff := cmp.Diff(test.expected, ticks); diff != "" {
+				t.Errorf("Ticks mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestSubscribeChan
+// Channel subscriptions get the previous values, drop the events that don't
+// fit and are closed by Unsubscribe. Negative sizes are refused
+func TestSubscribeChan(t *testing.T) {
+	// Arrange
+	ibt := openTestIBT(t, writeTestIBT(t, 10))
+	sub, events, err := ibt.SubscribeChan("Speed", EveryTick(), 2)
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	_, _, errArray := ibt.SubscribeChan("CarIdxLap", OnCross(1), 1)
+	_, _, errUnknown := ibt.SubscribeChan("Unknown", OnChange(), 1)
+	_, _, errSize := ibt.SubscribeChan("Speed", OnChange(), -1)
+
+	// Act
+	for k := 0; k < 3; k++ {
+		ibt.Update(time.Second)
+	}
+	sub.Unsubscribe()
+	ibt.Update(time.Second)
+	var got []VarEvent
+	for e := range events {
+		got = append(got, e)
+	}
+
+	// Assert
+	expected := []VarEvent{
+		{Name: "Speed", Tick: 1, Value: float32(0)},
+		{Name: "Speed", Tick: 2, Value: float32(1), Previous: float32(0)},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Events mismatch (-want +got):\n%s", diff)
+	}
+	if errArray == nil || errUnknown == nil {
+		t.Errorf("Expected arrays and unknown variables to be refused")
+	}
+	if errSize == nil {
+		t.Errorf("Expected a negative channel size to be refused")
+	}
+}
@@ -0,0 +1,63 @@
+package goirsdk
+
+import (
+	"fmt"
+	"io"
+)
+
+// SubsetVars builds a compact variable layout holding only the named
+// variables. They keep the order they have in the source and are aligned to
+// the size of their type, the returned length is the new BufLen
+func (i *IBT) SubsetVars(names []string) ([]Var, int32, error) {
+	wanted := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		v, ok := i.Vars.Vars[name]
+		if !ok || v.Count == 0 {
+			return nil, 0, fmt.Errorf("variable %s does not exist", name)
+		}
+		wanted[name] = struct{}{}
+	}
+
+	var vars []Var
+	for _, v := range i.SortedVars() {
+		if _, ok := wanted[v.Name]; ok {
+			vars = append(vars, v)
+		}
+	}
+
+	vars, bufLen := packVars(vars)
+	return vars, bufLen, nil
+}
+
+// packVars lays out vars one after the other, keeping their order and aligning
+// each one to the size of its type. Returns the new layout and its length
+func packVars(vars []Var) ([]Var, int32) {
+	packed := make([]Var, 0, len(vars))
+	var offset int32
+	for _, v := range vars {
+		size := int32(VarTypes[int(v.Type)].Size)
+		if rem := offset % size; rem != 0 {
+			offset += size - rem
+		}
+
+		v.Offset = offset
+		v.Value = nil
+		packed = append(packed, v)
+		offset += v.Count * size
+	}
+
+	return packed, offset
+}
+
+// Subset writes a new IBT file into dst that only holds the named variables.
+// Offsets and BufLen are recomputed so the data frames are as small as
+// possible, keep the SessionTime and Lap variables for a meaningful disk sub
+// header
+func (i *IBT) Subset(dst io.WriterAt, names []string) error {
+	vars, bufLen, err := i.SubsetVars(names)
+	if err != nil {
+		return err
+	}
+
+	return i.rewrite(dst, nil, vars, bufLen, nil)
+}
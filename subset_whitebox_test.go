@@ -0,0 +1,70 @@
+package goirsdk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestSubset_KeepsChosenVariables
+// The rewritten file only has the chosen variables, packed and aligned
+func TestSubset_KeepsChosenVariables(t *testing.T) {
+	// Arrange
+	src := openTestIBT(t, writeTestIBT(t, 90))
+	path := filepath.Join(t.TempDir(), "subset.ibt")
+	dst, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create output: %v", err)
+	}
+	defer dst.Close()
+
+	// Act
+	err = src.Subset(dst, []string{"CarIdxLap", "Speed", "SessionTime", "Lap"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Error writing subset: %v", err)
+	}
+	subset := openTestIBT(t, path)
+	offsets := map[string]int32{}
+	for _, v := range subset.SortedVars() {
+		offsets[v.Name] = v.Offset
+	}
+	expectedOffsets := map[string]int32{"SessionTime": 0, "Lap": 8, "Speed": 12, "CarIdxLap": 16}
+	if !cmp.Equal(expectedOffsets, offsets) {
+		t.Fatalf("Expected:\n%v\nGot:\n%v\n", expectedOffsets, offsets)
+	}
+	if subset.Headers.BufLen != 32 {
+		t.Fatalf("Expected BufLen 32, got %d", subset.Headers.BufLen)
+	}
+	if subset.SubHeaders.RecordCount != 90 || subset.SubHeaders.LapCount != 2 {
+		t.Fatalf("Unexpected sub headers:\n%s", subset.SubHeaders.ToString())
+	}
+
+	for tick := 0; tick < 61; tick++ {
+		_, err = subset.Update(0)
+		if err != nil {
+			t.Fatalf("Error reading subset data: %v", err)
+		}
+	}
+	if v := subset.Vars.Vars["Speed"].Value.(float32); v != 60 {
+		t.Fatalf("Expected Speed 60, got %f", v)
+	}
+	if v := subset.Vars.Vars["CarIdxLap"].Value; !cmp.Equal(v, []int32{1, 2, 3, 4}) {
+		t.Fatalf("Expected CarIdxLap [1 2 3 4], got %v", v)
+	}
+}
+
+// TestSubset_UnknownVariable
+// Asking for a variable the file doesn't have fails
+func TestSubset_UnknownVariable(t *testing.T) {
+	src := openTestIBT(t, writeTestIBT(t, 1))
+
+	_, _, err := src.SubsetVars([]string{"Speed", "Nope"})
+
+	if err == nil {
+		t.Fatalf("Expected an error for an unknown variable")
+	}
+}
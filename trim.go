@@ -0,0 +1,155 @@
+package goirsdk
+
+import (
+	"fmt"
+	"io"
+)
+
+// FrameSelector decides if the data frame of a given tick is kept when
+// rewriting a telemetry file
+type FrameSelector func(i *IBT, tick int32, frame []byte) bool
+
+// SelectTicks keeps the frames with from <= tick < to
+func SelectTicks(from, to int32) FrameSelector {
+	return func(_ *IBT, tick int32, _ []byte) bool {
+		return tick >= from && tick < to
+	}
+}
+
+// SelectSessionTime keeps the frames with from <= SessionTime <= to
+func SelectSessionTime(from, to float64) FrameSelector {
+	return func(i *IBT, _ int32, frame []byte) bool {
+		t, ok := i.frameNumber(frame, "SessionTime")
+		return ok && t >= from && t <= to
+	}
+}
+
+// SelectLaps keeps the frames in which the Lap variable is one of laps
+func SelectLaps(laps ...int) FrameSelector {
+	wanted := make(map[int]struct{}, len(laps))
+	for _, lap := range laps {
+		wanted[lap] = struct{}{}
+	}
+
+	return func(i *IBT, _ int32, frame []byte) bool {
+		lap, ok := i.frameNumber(frame, "Lap")
+		if !ok {
+			return false
+		}
+		_, ok = wanted[int(lap)]
+		return ok
+	}
+}
+
+// SelectSession keeps the frames in which SessionNum is num
+func SelectSession(num int) FrameSelector {
+	return func(i *IBT, _ int32, frame []byte) bool {
+		n, ok := i.frameNumber(frame, "SessionNum")
+		return ok && int(n) == num
+	}
+}
+
+// SelectAll keeps the frames accepted by every one of the selectors
+func SelectAll(selectors ...FrameSelector) FrameSelector {
+	return func(i *IBT, tick int32, frame []byte) bool {
+		for _, keep := range selectors {
+			if !keep(i, tick, frame) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Trim writes the frames accepted by keep into dst as a new IBT file, with its
+// disk sub headers corrected for the kept frames. Only telemetry files can be
+// trimmed, live data has no past frames to pick from
+func (i *IBT) Trim(dst io.WriterAt, keep FrameSelector) error {
+	return i.rewrite(dst, nil, i.SortedVars(), i.Headers.BufLen, keep)
+}
+
+// rewrite copies the frames accepted by keep into dst as a new IBT file with
+// the given variable layout. Variables are matched by name, so vars may use
+// offsets and a buffer length different from the source. A nil keep keeps
+// every frame and a nil sessionInfo keeps the source's session info
+func (i *IBT) rewrite(dst io.WriterAt, sessionInfo []byte, vars []Var, bufLen int32, keep FrameSelector) error {
+	if i.source.Live() {
+		return fmt.Errorf("rewriting is only supported for telemetry files")
+	}
+
+	if sessionInfo == nil {
+		var err error
+		sessionInfo, err = i.rawSessionInfo()
+		if err != nil {
+			return err
+		}
+	}
+
+	headers := *i.Headers
+	headers.BufLen = bufLen
+	w, err := NewIBTWriter(dst, headers, *i.SubHeaders, vars, sessionInfo)
+	if err != nil {
+		return err
+	}
+
+	err = i.eachFrame(func(tick int32, frame []byte) error {
+		if keep != nil && !keep(i, tick, frame) {
+			return nil
+		}
+		return w.WriteFrame(i.remapFrame(frame, vars, bufLen))
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.Close()
+}
+
+// eachFrame calls fn with every data frame of a telemetry file, in order. It
+// stops at the first error returned by fn
+func (i *IBT) eachFrame(fn func(tick int32, frame []byte) error) error {
+	for tick := int32(0); ; tick++ {
+		frame, err := i.ReadFrame(tick)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read frame %d: %v", tick, err)
+		}
+
+		err = fn(tick, frame)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// remapFrame moves the values of a source frame into a frame with the layout
+// of vars
+func (i *IBT) remapFrame(frame []byte, vars []Var, bufLen int32) []byte {
+	if bufLen == i.Headers.BufLen && sameLayout(i.Vars.Vars, vars) {
+		return frame
+	}
+
+	out := make([]byte, bufLen)
+	for _, v := range vars {
+		src, ok := i.Vars.Vars[v.Name]
+		if !ok {
+			continue
+		}
+		size := src.Count * int32(VarTypes[int(src.Type)].Size)
+		copy(out[v.Offset:v.Offset+size], frame[src.Offset:src.Offset+size])
+	}
+
+	return out
+}
+
+// sameLayout tells if every one of vars sits at the same offset in the source
+func sameLayout(src map[string]Var, vars []Var) bool {
+	for _, v := range vars {
+		if s, ok := src[v.Name]; !ok || s.Offset != v.Offset {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package goirsdk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestTrim_Selectors
+// Trimming keeps only the selected frames and corrects the disk sub headers
+func TestTrim_Selectors(t *testing.T) {
+	tests := []struct {
+		name        string
+		keep        FrameSelector
+		recordCount int32
+		lapCount    int32
+		startTime   float64
+	}{
+		{"ticks", SelectTicks(10, 20), 10, 1, 100 + 10.0/60},
+		{"session time", SelectSessionTime(101, 102), 61, 2, 101},
+		{"laps", SelectLaps(1), 60, 1, 101},
+		{"session", SelectSession(1), 60, 1, 102},
+		{"all", SelectAll(SelectLaps(0, 1), SelectTicks(50, 70)), 20, 2, 100 + 50.0/60},
+	}
+
+	src := openTestIBT(t, writeTestIBT(t, 180))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			path := filepath.Join(t.TempDir(), "trimmed.ibt")
+			dst, err := os.Create(path)
+			if err != nil {
+				t.Fatalf("Failed to create output: %v", err)
+			}
+			defer dst.Close()
+
+			// Act
+			err = src.Trim(dst, tt.keep)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Error trimming: %v", err)
+			}
+			trimmed := openTestIBT(t, path)
+			sub := trimmed.SubHeaders
+			if sub.RecordCount != tt.recordCount || sub.LapCount != tt.lapCount || sub.StartTime != tt.startTime {
+				t.Fatalf("Unexpected sub headers:\n%s", sub.ToString())
+			}
+			if sub.StartDate != src.SubHeaders.StartDate {
+				t.Fatalf("Expected StartDate %d, got %d", src.SubHeaders.StartDate, sub.StartDate)
+			}
+
+			_, err = trimmed.Update(0)
+			if err != nil {
+				t.Fatalf("Error reading trimmed data: %v", err)
+			}
+			if v := trimmed.Vars.Vars["SessionTime"].Value.(float64); v != tt.startTime {
+				t.Fatalf("Expected first SessionTime %f, got %f", tt.startTime, v)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package goirsdk
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestDecodeVar_SignedInts
+// Single ints and int arrays are both decoded as signed values, bitfields stay
+// unsigned
+func TestDecodeVar_SignedInts(t *testing.T) {
+	// Arrange
+	frame := make([]byte, 12)
+	binary.LittleEndian.PutUint32(frame[0:], 0xffffffff)
+	binary.LittleEndian.PutUint32(frame[4:], 0xfffffffe)
+	binary.LittleEndian.PutUint32(frame[8:], 3)
+
+	tests := []struct {
+		name     string
+		v        Var
+		expected interface{}
+	}{
+		{name: "Single int", v: Var{Type: IRSDK_int, Offset: 0, Count: 1}, expected: -1},
+		{name: "Int array", v: Var{Type: IRSDK_int, Offset: 4, Count: 2}, expected: []int32{-2, 3}},
+		{name: "Bitfield", v: Var{Type: IRSDK_bitField, Offset: 0, Count: 1}, expected: "0xffffffff"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Act
+			got := decodeVar(test.v, frame)
+
+			// Assert
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("Value mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
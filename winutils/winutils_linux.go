@@ -1,53 +1,113 @@
-//go:build (linux && cgo) || (darwin && cgo)
-// +build linux,cgo darwin,cgo
-
-package winutils
-
-import (
-	"errors"
-	"sync"
-	"time"
-)
-
-const (
-	WAIT_OBJECT_0 = 0
-	WAIT_TIMEOUT  = 258
-)
-
-var (
-	once             sync.Once
-	ErrUnsupportedOS = errors.New("not found")
-)
-
-type utils struct {
-}
-
-// INITIALIZATION
-func newUtils() (*utils, error) {
-	return nil, ErrUnsupportedOS
-}
-
-func (u *utils) Close() {
-}
-
-// openEvent opens a windows.Handle for a given event
-func (u *utils) OpenEvent(eventName string) error {
-	return ErrUnsupportedOS
-}
-
-// OpenBroadcastChannel opens up a broadcast channel to send commands to iracing
-func (u *utils) OpenBroadcastChannel(name string) error {
-	return ErrUnsupportedOS
-}
-
-// INITIALIZATION
-
-// openEvent waits for a good response for some given time
-func (u *utils) CheckValidDataEvent(timeout time.Duration) bool {
-	return false
-}
-
-// SendBroadcastMessage sends a message trough the broadcast channel
-func (u *utils) SendBroadcastMessage(id, p1, p2 uintptr) error {
-  return ErrUnsupportedOS
-}
+//go:build (linux && cgo) || (darwin && cgo)
+// +build linux,cgo darwin,cgo
+
+package winutils
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ESilva15/goirsdk/sharedMem"
+)
+
+const (
+	WAIT_OBJECT_0 = 0
+	WAIT_TIMEOUT  = 258
+
+	// EventSize is the size of the shared memory standing in for a windows
+	// event, it holds a counter bumped every time the event is signaled
+	EventSize = 4
+
+	// eventPollInterval is how often the event counter is checked
+	eventPollInterval = time.Millisecond
+)
+
+var (
+	once             sync.Once
+	ErrUnsupportedOS = errors.New("not found")
+)
+
+// There are no named events on Linux, a live session emulator publishes the
+// data valid event as a counter in its own shared memory region instead
+type utils struct {
+	event     *sharedMem.Memory
+	lastEvent uint32
+}
+
+// INITIALIZATION
+func newUtils() (*utils, error) {
+	return &utils{}, nil
+}
+
+func (u *utils) Close() {
+	if u.event != nil {
+		u.event.Close()
+		u.event = nil
+	}
+}
+
+// openEvent opens the shared memory standing in for a given event
+func (u *utils) OpenEvent(eventName string) error {
+	event, err := sharedMem.Open(eventName, EventSize)
+	if err != nil {
+		return err
+	}
+	u.event = event
+
+	u.lastEvent, err = u.eventCount()
+	return err
+}
+
+// OpenBroadcastChannel opens up a broadcast channel to send commands to iracing
+// There's nothing to open on Linux, messages can't be sent anyway
+func (u *utils) OpenBroadcastChannel(name string) error {
+	return nil
+}
+
+// INITIALIZATION
+
+// eventCount reads how many times the event was signaled
+func (u *utils) eventCount() (uint32, error) {
+	var raw [EventSize]byte
+	_, err := u.event.ReadAt(raw[:], 0)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(raw[:]), nil
+}
+
+// openEvent waits for a good response for some given time
+func (u *utils) CheckValidDataEvent(timeout time.Duration) bool {
+	if u.event == nil {
+		return false
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		count, err := u.eventCount()
+		if err != nil {
+			return false
+		}
+		if count != u.lastEvent {
+			u.lastEvent = count
+			return true
+		}
+
+		if !time.Now().Before(deadline) {
+			return false
+		}
+		time.Sleep(min(eventPollInterval, time.Until(deadline)))
+	}
+}
+
+// broadcastChannel returns the id of the broadcast message, there's none
+func (u *utils) broadcastChannel() uintptr {
+	return 0
+}
+
+// SendBroadcastMessage sends a message trough the broadcast channel
+func (u *utils) SendBroadcastMessage(id, p1, p2 uintptr) error {
+	return ErrUnsupportedOS
+}
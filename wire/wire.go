@@ -0,0 +1,325 @@
+// Package wire is the compact encoding of the telemetry relay: the version and
+// channel negotiation of a connection and the frames, sent as the channels
+// that changed since the previous frame with a full keyframe now and then
+package wire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+const (
+	// VersionFull sends every frame whole
+	VersionFull uint8 = 1
+	// VersionDelta sends the channels that changed, with periodic keyframes
+	VersionDelta uint8 = 2
+
+	// DefaultKeyframeInterval is the number of frames between keyframes, a
+	// second at 60Hz
+	DefaultKeyframeInterval = 60
+
+	flagKeyframe byte = 1 // The frame holds every channel
+
+	maxListLen = 1 << 16 // Longest list of versions or channels accepted
+)
+
+var (
+	// ErrNoCommonVersion means the two ends support no version in common
+	ErrNoCommonVersion = errors.New("no common protocol version")
+	// ErrNeedKeyframe means a delta frame arrived before any keyframe
+	ErrNeedKeyframe = errors.New("delta frame before the first keyframe")
+)
+
+// Versions are the protocol versions this package speaks, preferred first
+var Versions = []uint8{VersionDelta, VersionFull}
+
+// Channel is a variable of the data frames, sent as a whole when any of its
+// bytes change
+type Channel struct {
+	Name   string
+	Offset int // Offset of the variable in the frame
+	Size   int // Size of the variable in bytes, all its entries
+}
+
+// Hello opens a connection, the client tells the versions it speaks and the
+// channels it wants
+type Hello struct {
+	Versions []uint8  // Versions supported, preferred first
+	Channels []string // Channels subscribed to, every channel when empty
+}
+
+// Welcome answers a Hello with the version picked and the channels granted
+type Welcome struct {
+	Version  uint8
+	Channels []string // Channels that will be sent, the unknown ones are left out
+}
+
+// Negotiate picks the first version of the client the server supports
+func Negotiate(client []uint8, server []uint8) (uint8, error) {
+	for _, v := range client {
+		if slices.Contains(server, v) {
+			return v, nil
+		}
+	}
+	return 0, ErrNoCommonVersion
+}
+
+// Subscribe returns the channels of all named in names, in the order of all.
+// Every channel is returned when names is empty
+func Subscribe(all []Channel, names []string) []Channel {
+	if len(names) == 0 {
+		return all
+	}
+
+	var selected []Channel
+	for _, c := range all {
+		if slices.Contains(names, c.Name) {
+			selected = append(selected, c)
+		}
+	}
+	return selected
+}
+
+// MarshalBinary encodes the Hello
+func (h Hello) MarshalBinary() ([]byte, error) {
+	buf := binary.AppendUvarint(nil, uint64(len(h.Versions)))
+	buf = append(buf, h.Versions...)
+	return appendStrings(buf, h.Channels), nil
+}
+
+// UnmarshalBinary decodes a Hello
+func (h *Hello) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	n, err := readLen(r)
+	if err != nil {
+		return fmt.Errorf("invalid hello: %v", err)
+	}
+	h.Versions = make([]uint8, n)
+	_, err = readFull(r, h.Versions)
+	if err != nil {
+		return fmt.Errorf("invalid hello: %v", err)
+	}
+	h.Channels, err = readStrings(r)
+	if err != nil {
+		return fmt.Errorf("invalid hello: %v", err)
+	}
+	return nil
+}
+
+// MarshalBinary encodes the Welcome
+func (w Welcome) MarshalBinary() ([]byte, error) {
+	return appendStrings([]byte{w.Version}, w.Channels), nil
+}
+
+// UnmarshalBinary decodes a Welcome
+func (w *Welcome) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("invalid welcome: empty")
+	}
+	w.Version = data[0]
+	var err error
+	w.Channels, err = readStrings(bytes.NewReader(data[1:]))
+	if err != nil {
+		return fmt.Errorf("invalid welcome: %v", err)
+	}
+	return nil
+}
+
+// appendStrings appends a length prefixed list of strings
+func appendStrings(buf []byte, list []string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(list)))
+	for _, s := range list {
+		buf = binary.AppendUvarint(buf, uint64(len(s)))
+		buf = append(buf, s...)
+	}
+	return buf
+}
+
+// readStrings reads a list written by appendStrings
+func readStrings(r *bytes.Reader) ([]string, error) {
+	n, err := readLen(r)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]string, 0, n)
+	for k := 0; k < n; k++ {
+		size, err := readLen(r)
+		if err != nil {
+			return nil, err
+		}
+		s := make([]byte, size)
+		_, err = readFull(r, s)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, string(s))
+	}
+	return list, nil
+}
+
+// readLen reads a length, refusing the unreasonable ones
+func readLen(r *bytes.Reader) (int, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, err
+	}
+	if n > maxListLen {
+		return 0, fmt.Errorf("length %d is too large", n)
+	}
+	return int(n), nil
+}
+
+// Encoder encodes the frames of a connection, it remembers the last frame
+// sent to only send the channels that changed
+type Encoder struct {
+	channels []Channel
+	version  uint8
+	interval int
+	prev     []byte // prev holds the channels of the last frame, one after the other
+	sinceKey int    // sinceKey counts the frames since the last keyframe
+}
+
+// NewEncoder creates an encoder for a version and the channels of a
+// connection. A keyframe is sent every interval frames, DefaultKeyframeInterval
+// when 0
+func NewEncoder(version uint8, channels []Channel, interval int) (*Encoder, error) {
+	if version != VersionFull && version != VersionDelta {
+		return nil, fmt.Errorf("unsupported version %d", version)
+	}
+	if interval <= 0 {
+		interval = DefaultKeyframeInterval
+	}
+	return &Encoder{channels: channels, version: version, interval: interval}, nil
+}
+
+// ForceKeyframe makes the next frame a keyframe
+func (e *Encoder) ForceKeyframe() {
+	e.prev = nil
+}
+
+// Encode appends the encoding of a frame to dst. The frame is the whole data
+// buffer, laid out like the variable headers say
+func (e *Encoder) Encode(dst []byte, tick int32, frame []byte) ([]byte, error) {
+	for _, c := range e.channels {
+		if c.Offset+c.Size > len(frame) {
+			return nil, fmt.Errorf("channel %s is past the end of the %d bytes frame", c.Name, len(frame))
+		}
+	}
+
+	keyframe := e.version == VersionFull || e.prev == nil || e.sinceKey+1 >= e.interval
+	if keyframe {
+		dst = append(dst, flagKeyframe)
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(tick))
+		start := len(dst)
+		for _, c := range e.channels {
+			dst = append(dst, frame[c.Offset:c.Offset+c.Size]...)
+		}
+		e.prev = append(e.prev[:0], dst[start:]...)
+		e.sinceKey = 0
+		return dst, nil
+	}
+
+	dst = append(dst, 0)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(tick))
+
+	var changed []int
+	pos := 0
+	for k, c := range e.channels {
+		cur := frame[c.Offset : c.Offset+c.Size]
+		if !bytes.Equal(cur, e.prev[pos:pos+c.Size]) {
+			changed = append(changed, k)
+			copy(e.prev[pos:], cur)
+		}
+		pos += c.Size
+	}
+
+	// Changed channels are sent as the gap from the previous one and the data
+	dst = binary.AppendUvarint(dst, uint64(len(changed)))
+	last := -1
+	for _, k := range changed {
+		c := e.channels[k]
+		dst = binary.AppendUvarint(dst, uint64(k-last-1))
+		dst = append(dst, frame[c.Offset:c.Offset+c.Size]...)
+		last = k
+	}
+	e.sinceKey++
+
+	return dst, nil
+}
+
+// Decoder rebuilds the frames of a connection
+type Decoder struct {
+	channels []Channel
+	frame    []byte
+	ready    bool // ready is set once a keyframe was decoded
+}
+
+// NewDecoder creates a decoder for the channels of a connection, rebuilding
+// frames of bufLen bytes. The bytes of the channels not sent stay zero
+func NewDecoder(channels []Channel, bufLen int) (*Decoder, error) {
+	for _, c := range channels {
+		if c.Offset < 0 || c.Size < 0 || c.Offset+c.Size > bufLen {
+			return nil, fmt.Errorf("channel %s is out of the %d bytes frame", c.Name, bufLen)
+		}
+	}
+	return &Decoder{channels: channels, frame: make([]byte, bufLen)}, nil
+}
+
+// Decode decodes a frame. The frame returned is a new slice every call
+func (d *Decoder) Decode(data []byte) (int32, []byte, error) {
+	if len(data) < 5 {
+		return 0, nil, fmt.Errorf("frame of %d bytes is too short", len(data))
+	}
+	flags := data[0]
+	tick := int32(binary.LittleEndian.Uint32(data[1:]))
+	r := bytes.NewReader(data[5:])
+
+	if flags&flagKeyframe != 0 {
+		for _, c := range d.channels {
+			_, err := readFull(r, d.frame[c.Offset:c.Offset+c.Size])
+			if err != nil {
+				return 0, nil, fmt.Errorf("truncated keyframe: %v", err)
+			}
+		}
+		d.ready = true
+		return tick, slices.Clone(d.frame), nil
+	}
+
+	if !d.ready {
+		return 0, nil, ErrNeedKeyframe
+	}
+	n, err := binary.ReadUvarint(r)
+	if err != nil || n > uint64(len(d.channels)) {
+		return 0, nil, fmt.Errorf("invalid count of changed channels")
+	}
+	k := -1
+	for ; n > 0; n-- {
+		gap, err := binary.ReadUvarint(r)
+		if err != nil || gap >= uint64(len(d.channels)-k-1) {
+			return 0, nil, fmt.Errorf("invalid changed channel")
+		}
+		k += int(gap) + 1
+		c := d.channels[k]
+		_, err = readFull(r, d.frame[c.Offset:c.Offset+c.Size])
+		if err != nil {
+			return 0, nil, fmt.Errorf("truncated delta frame: %v", err)
+		}
+	}
+
+	return tick, slices.Clone(d.frame), nil
+}
+
+// readFull fills p from r
+func readFull(r *bytes.Reader, p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if r.Len() < len(p) {
+		return 0, fmt.Errorf("%d bytes missing", len(p)-r.Len())
+	}
+	return r.Read(p)
+}
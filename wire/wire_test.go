@@ -0,0 +1,118 @@
+package wire
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestHello_RoundTrip
+// A Hello and a Welcome survive their encoding
+func TestHello_RoundTrip(t *testing.T) {
+	// Arrange
+	hello := Hello{Versions: Versions, Channels: []string{"Speed", "RPM"}}
+	welcome := Welcome{Version: VersionDelta, Channels: []string{"Speed"}}
+
+	// Act
+	rawHello, _ := hello.MarshalBinary()
+	rawWelcome, _ := welcome.MarshalBinary()
+	var gotHello Hello
+	var gotWelcome Welcome
+	errHello := gotHello.UnmarshalBinary(rawHello)
+	errWelcome := gotWelcome.UnmarshalBinary(rawWelcome)
+
+	// Assert
+	if errHello != nil || errWelcome != nil {
+		t.Fatalf("Failed to decode: %v, %v", errHello, errWelcome)
+	}
+	if diff := cmp.Diff(hello, gotHello); diff != "" {
+		t.Errorf("Hello mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(welcome, gotWelcome); diff != "" {
+		t.Errorf("Welcome mismatch (-want +got):\n%s", diff)
+	}
+	if err := gotHello.UnmarshalBinary(rawHello[:len(rawHello)-1]); err == nil {
+		t.Errorf("Expected a truncated hello to fail")
+	}
+}
+
+// TestNegotiate
+// The first version of the client known by the server is picked
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name     string
+		client   []uint8
+		expected uint8
+		err      error
+	}{
+		{name: "Preferred", client: []uint8{3, VersionDelta, VersionFull}, expected: VersionDelta},
+		{name: "Older client", client: []uint8{VersionFull}, expected: VersionFull},
+		{name: "Nothing in common", client: []uint8{9}, err: ErrNoCommonVersion},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Act
+			version, err := Negotiate(test.client, Versions)
+
+			// Assert
+			if version != test.expected || !errors.Is(err, test.err) {
+				t.Errorf("Expected version %d (%v), got %d (%v)", test.expected, test.err, version, err)
+			}
+		})
+	}
+}
+
+// TestEncoder_Delta
+// Frames only carry the channels that changed between keyframes, and decode
+// to the subscribed channels of the original frames
+func TestEncoder_Delta(t *testing.T) {
+	// Arrange
+	channels := []Channel{{Name: "A", Offset: 0, Size: 4}, {Name: "B", Offset: 4, Size: 2}, {Name: "C", Offset: 6, Size: 2}}
+	frames := [][]byte{
+		{1, 0, 0, 0, 5, 5, 7, 7},
+		{1, 0, 0, 0, 5, 5, 7, 8},
+		{1, 0, 0, 0, 5, 5, 7, 8},
+		{2, 0, 0, 0, 5, 5, 7, 9},
+	}
+	enc, _ := NewEncoder(VersionDelta, channels[1:], 3)
+	dec, err := NewDecoder(channels[1:], 8)
+	if err != nil {
+		t.Fatalf("Failed to create decoder: %v", err)
+	}
+
+	// Act
+	var sizes []int
+	var decoded [][]byte
+	for k, frame := range frames {
+		data, err := enc.Encode(nil, int32(k), frame)
+		if err != nil {
+			t.Fatalf("Failed to encode frame %d: %v", k, err)
+		}
+		sizes = append(sizes, len(data))
+		tick, got, err := dec.Decode(data)
+		if err != nil || tick != int32(k) {
+			t.Fatalf("Failed to decode frame %d: tick %d, %v", k, tick, err)
+		}
+		decoded = append(decoded, got)
+	}
+
+	// Assert
+	// keyframe, one channel changed, nothing changed, keyframe
+	if diff := cmp.Diff([]int{9, 9, 6, 9}, sizes); diff != "" {
+		t.Errorf("Frame sizes mismatch (-want +got):\n%s", diff)
+	}
+	for k, frame := range frames {
+		want := append([]byte{0, 0, 0, 0}, frame[4:]...)
+		if diff := cmp.Diff(want, decoded[k]); diff != "" {
+			t.Errorf("Frame %d mismatch (-want +got):\n%s", k, diff)
+		}
+	}
+
+	delta, _ := enc.Encode(nil, 4, frames[0])
+	fresh, _ := NewDecoder(channels[1:], 8)
+	if _, _, err := fresh.Decode(delta); !errors.Is(err, ErrNeedKeyframe) {
+		t.Errorf("Expected ErrNeedKeyframe, got %v", err)
+	}
+}